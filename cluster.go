@@ -2,6 +2,7 @@ package gorethink
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -30,14 +31,26 @@ type Cluster struct {
 	closed bool
 
 	nodeIndex int64
+
+	failedMu    sync.Mutex
+	failedHosts map[string]time.Time
+
+	// nearest selects the host outdated reads are sent to when no
+	// HostSelector is set.
+	nearest HostSelector
 }
 
+// hostRetryDelay is how long a host which failed is not selected by a
+// HostSelector.
+const hostRetryDelay = 30 * time.Second
+
 // NewCluster creates a new cluster by connecting to the given hosts.
 func NewCluster(hosts []Host, opts *ConnectOpts) (*Cluster, error) {
 	c := &Cluster{
-		hp:    hostpool.NewEpsilonGreedy([]string{}, opts.HostDecayDuration, &hostpool.LinearEpsilonValueCalculator{}),
-		seeds: hosts,
-		opts:  opts,
+		hp:      hostpool.NewEpsilonGreedy([]string{}, opts.HostDecayDuration, &hostpool.LinearEpsilonValueCalculator{}),
+		seeds:   hosts,
+		opts:    opts,
+		nearest: NewLatencyHostSelector(),
 	}
 
 	// Attempt to connect to each host and discover any additional hosts if host
@@ -59,19 +72,24 @@ func NewCluster(hosts []Host, opts *ConnectOpts) (*Cluster, error) {
 
 // Query executes a ReQL query using the cluster to connect to the database
 func (c *Cluster) Query(ctx context.Context, q Query) (cursor *Cursor, err error) {
-	for i := 0; i < c.numRetries(); i++ {
+	policy := c.retryPolicy(q)
+	for i := 0; i < c.maxAttempts(policy); i++ {
+		if i > 0 && !waitRetry(ctx, policy, i) {
+			break
+		}
+
 		var node *Node
-		var hpr hostpool.HostPoolResponse
+		var mark func(error)
 
-		node, hpr, err = c.GetNextNode()
+		node, mark, err = c.selectNode(q)
 		if err != nil {
 			return nil, err
 		}
 
 		cursor, err = node.Query(ctx, q)
-		hpr.Mark(err)
+		mark(err)
 
-		if !shouldRetryQuery(q, err) {
+		if !shouldRetryQuery(q, err, policy) {
 			break
 		}
 	}
@@ -81,19 +99,24 @@ func (c *Cluster) Query(ctx context.Context, q Query) (cursor *Cursor, err error
 
 // Exec executes a ReQL query using the cluster to connect to the database
 func (c *Cluster) Exec(ctx context.Context, q Query) (err error) {
-	for i := 0; i < c.numRetries(); i++ {
+	policy := c.retryPolicy(q)
+	for i := 0; i < c.maxAttempts(policy); i++ {
+		if i > 0 && !waitRetry(ctx, policy, i) {
+			break
+		}
+
 		var node *Node
-		var hpr hostpool.HostPoolResponse
+		var mark func(error)
 
-		node, hpr, err = c.GetNextNode()
+		node, mark, err = c.selectNode(q)
 		if err != nil {
 			return err
 		}
 
 		err = node.Exec(ctx, q)
-		hpr.Mark(err)
+		mark(err)
 
-		if !shouldRetryQuery(q, err) {
+		if !shouldRetryQuery(q, err, policy) {
 			break
 		}
 	}
@@ -101,19 +124,36 @@ func (c *Cluster) Exec(ctx context.Context, q Query) (err error) {
 	return err
 }
 
+// markNode records the result of a query in the host pool. Only connection
+// errors are recorded as failures so that hosts are not avoided when the
+// database returns a runtime error, such as when a table does not exist.
+func markNode(hpr hostpool.HostPoolResponse, err error) {
+	if isConnectionError(err) {
+		hpr.Mark(err)
+	} else {
+		hpr.Mark(nil)
+	}
+}
+
 // Server returns the server name and server UUID being used by a connection.
-func (c *Cluster) Server() (response ServerResponse, err error) {
+func (c *Cluster) Server() (ServerResponse, error) {
+	info, err := c.ServerInfo()
+	return info.serverResponse(), err
+}
+
+// ServerInfo returns information about the server being used by a connection.
+func (c *Cluster) ServerInfo() (response ServerInfo, err error) {
 	for i := 0; i < c.numRetries(); i++ {
 		var node *Node
-		var hpr hostpool.HostPoolResponse
+		var mark func(error)
 
-		node, hpr, err = c.GetNextNode()
+		node, mark, err = c.selectNode(Query{})
 		if err != nil {
-			return ServerResponse{}, err
+			return ServerInfo{}, err
 		}
 
-		response, err = node.Server()
-		hpr.Mark(err)
+		response, err = node.ServerInfo()
+		mark(err)
 
 		// This query should not fail so retry if any error is detected
 		if err == nil {
@@ -124,6 +164,19 @@ func (c *Cluster) Server() (response ServerResponse, err error) {
 	return response, err
 }
 
+// NoReplyWait ensures that previous queries with the noreply flag have been
+// processed by every node in the cluster.
+func (c *Cluster) NoReplyWait() error {
+	var err error
+	for _, node := range c.GetNodes() {
+		if nodeErr := node.NoReplyWait(); nodeErr != nil && err == nil {
+			err = nodeErr
+		}
+	}
+
+	return err
+}
+
 // SetInitialPoolCap sets the initial capacity of the connection pool.
 func (c *Cluster) SetInitialPoolCap(n int) {
 	for _, node := range c.GetNodes() {
@@ -152,6 +205,16 @@ func (c *Cluster) Close(optArgs ...CloseOpts) error {
 		return nil
 	}
 
+	// Share the timeout between all nodes
+	if len(optArgs) >= 1 && optArgs[0].Timeout > 0 && optArgs[0].ctx == nil {
+		opts := optArgs[0]
+		var cancel context.CancelFunc
+		opts.ctx, cancel = context.WithTimeout(context.Background(), opts.Timeout)
+		defer cancel()
+
+		optArgs = []CloseOpts{opts}
+	}
+
 	for _, node := range c.GetNodes() {
 		err := node.Close(optArgs...)
 		if err != nil {
@@ -191,7 +254,7 @@ func (c *Cluster) discover() {
 // This function will block until the query fails
 func (c *Cluster) listenForNodeChanges() error {
 	// Start listening to changes from a random active node
-	node, hpr, err := c.GetNextNode()
+	node, mark, err := c.selectNode(Query{})
 	if err != nil {
 		return err
 	}
@@ -207,7 +270,7 @@ func (c *Cluster) listenForNodeChanges() error {
 
 	cursor, err := node.Query(context.Background(), q) // no need for timeout due to Changes()
 	if err != nil {
-		hpr.Mark(err)
+		mark(err)
 		return err
 	}
 
@@ -217,37 +280,50 @@ func (c *Cluster) listenForNodeChanges() error {
 		OldVal nodeStatus `gorethink:"old_val"`
 	}
 	for cursor.Next(&result) {
-		addr := fmt.Sprintf("%s:%d", result.NewVal.Network.Hostname, result.NewVal.Network.ReqlPort)
-		addr = strings.ToLower(addr)
-
-		switch result.NewVal.Status {
-		case "connected":
-			// Connect to node using exponential backoff (give up after waiting 5s)
-			// to give the node time to start-up.
-			b := backoff.NewExponentialBackOff()
-			b.MaxElapsedTime = time.Second * 5
-
-			backoff.Retry(func() error {
-				node, err := c.connectNodeWithStatus(result.NewVal)
-				if err == nil {
-					if !c.nodeExists(node) {
-						c.addNode(node)
+		c.handleNodeChange(result.NewVal, result.OldVal)
+	}
 
-						Log.WithFields(logrus.Fields{
-							"id":   node.ID,
-							"host": node.Host.String(),
-						}).Debug("Connected to node")
-					}
+	err = cursor.Err()
+	mark(err)
+	return err
+}
+
+// handleNodeChange connects to servers which have connected to the cluster
+// and removes servers which have been removed from it.
+func (c *Cluster) handleNodeChange(newVal, oldVal nodeStatus) {
+	addr := fmt.Sprintf("%s:%d", newVal.Network.Hostname, newVal.Network.ReqlPort)
+	addr = strings.ToLower(addr)
+
+	switch newVal.Status {
+	case "connected":
+		// Connect to node using exponential backoff (give up after waiting 5s)
+		// to give the node time to start-up.
+		b := backoff.NewExponentialBackOff()
+		b.MaxElapsedTime = time.Second * 5
+
+		backoff.Retry(func() error {
+			node, err := c.connectNodeWithStatus(newVal)
+			if err == nil {
+				if !c.nodeExists(node) {
+					c.addNode(node)
+
+					Log.WithFields(logrus.Fields{
+						"id":   node.ID,
+						"host": node.Host.String(),
+					}).Debug("Connected to node")
 				}
+			}
 
-				return err
-			}, b)
+			return err
+		}, b)
+	case "":
+		// The server has been removed from the cluster
+		if oldVal.ID != "" && c.removeNode(oldVal.ID) {
+			Log.WithFields(logrus.Fields{
+				"id": oldVal.ID,
+			}).Debug("Removed node")
 		}
 	}
-
-	err = cursor.Err()
-	hpr.Mark(err)
-	return err
 }
 
 func (c *Cluster) connectNodes(hosts []Host) error {
@@ -310,7 +386,7 @@ func (c *Cluster) connectNodes(hosts []Host) error {
 				}
 			}
 		} else {
-			svrRsp, err := conn.Server()
+			svrRsp, err := conn.ServerInfo()
 			if err != nil {
 				attemptErr = err
 				Log.Warnf("Error fetching server ID: %s", err)
@@ -399,6 +475,17 @@ func (c *Cluster) IsConnected() bool {
 	return (len(c.GetNodes()) > 0) && !closed
 }
 
+// Stats returns the combined statistics of the connection pools of all nodes
+// in the cluster.
+func (c *Cluster) Stats() PoolStats {
+	var stats PoolStats
+	for _, node := range c.GetNodes() {
+		stats = stats.add(node.Stats())
+	}
+
+	return stats
+}
+
 // AddSeeds adds new seed hosts to the cluster.
 func (c *Cluster) AddSeeds(hosts []Host) {
 	c.mu.Lock()
@@ -414,6 +501,104 @@ func (c *Cluster) getSeeds() []Host {
 	return seeds
 }
 
+// selectNode returns the node the next query should be sent to along with a
+// function which must be called with the result of the query. If a
+// HostSelector is set in ConnectOpts then it is used to select the node,
+// otherwise outdated reads are sent to the host with the lowest latency and
+// all other queries are sent to a node selected using the cluster's host pool.
+func (c *Cluster) selectNode(q Query) (*Node, func(error), error) {
+	selector := c.opts.HostSelector
+	if selector == nil && q.Opts["read_mode"] == ReadModeOutdated {
+		selector = c.nearest
+	}
+	if selector == nil {
+		node, hpr, err := c.GetNextNode()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return node, func(err error) { markNode(hpr, err) }, nil
+	}
+
+	if !c.IsConnected() {
+		return nil, nil, ErrNoConnections
+	}
+
+	nodes := map[string]*Node{}
+	for _, node := range c.GetNodes() {
+		if !node.Closed() {
+			nodes[node.Host.String()] = node
+		}
+	}
+	hosts := c.selectableHosts(nodes)
+	if len(hosts) == 0 {
+		return nil, nil, ErrNoConnections
+	}
+
+	host := selector.Select(hosts)
+	node, ok := nodes[host]
+	if !ok {
+		return nil, nil, ErrNoConnections
+	}
+
+	start := time.Now()
+	return node, func(err error) {
+		if !isConnectionError(err) {
+			err = nil
+		}
+
+		c.failedMu.Lock()
+		if err != nil {
+			if c.failedHosts == nil {
+				c.failedHosts = map[string]time.Time{}
+			}
+			c.failedHosts[host] = time.Now()
+		} else {
+			delete(c.failedHosts, host)
+		}
+		c.failedMu.Unlock()
+
+		selector.Mark(host, err, time.Since(start))
+	}, nil
+}
+
+// selectableHosts returns the hosts which can be passed to a HostSelector
+// sorted by address, hosts which failed within hostRetryDelay are excluded
+// unless every host has failed.
+func (c *Cluster) selectableHosts(nodes map[string]*Node) []HostInfo {
+	c.failedMu.Lock()
+	defer c.failedMu.Unlock()
+
+	now := time.Now()
+	all := make([]HostInfo, 0, len(nodes))
+	healthy := make([]HostInfo, 0, len(nodes))
+	for host, node := range nodes {
+		info := HostInfo{
+			Address: host,
+			Stats:   node.Stats(),
+		}
+
+		all = append(all, info)
+		if failedAt, ok := c.failedHosts[host]; !ok || now.Sub(failedAt) >= hostRetryDelay {
+			healthy = append(healthy, info)
+		}
+	}
+
+	hosts := healthy
+	if len(hosts) == 0 {
+		hosts = all
+	}
+	sort.Sort(hostInfosByAddress(hosts))
+
+	return hosts
+}
+
+type hostInfosByAddress []HostInfo
+
+func (h hostInfosByAddress) Len() int           { return len(h) }
+func (h hostInfosByAddress) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h hostInfosByAddress) Less(i, j int) bool { return h[i].Address < h[j].Address }
+
 // GetNextNode returns a random node on the cluster
 func (c *Cluster) GetNextNode() (*Node, hostpool.HostPoolResponse, error) {
 	if !c.IsConnected() {
@@ -486,28 +671,31 @@ func (c *Cluster) setNodes(nodes []*Node) {
 	c.mu.Unlock()
 }
 
-func (c *Cluster) removeNode(nodeID string) {
+// removeNode removes the node with the given ID from the cluster and closes
+// it, returning false if the node was not found.
+func (c *Cluster) removeNode(nodeID string) bool {
 	nodes := c.GetNodes()
-	nodeArray := make([]*Node, len(nodes)-1)
-	count := 0
+	remaining := make([]*Node, 0, len(nodes))
+	removed := []*Node{}
 
-	// Add nodes that are not in remove list.
 	for _, n := range nodes {
-		if n.ID != nodeID {
-			nodeArray[count] = n
-			count++
+		if n.ID == nodeID {
+			removed = append(removed, n)
+		} else {
+			remaining = append(remaining, n)
 		}
 	}
 
-	// Do sanity check to make sure assumptions are correct.
-	if count < len(nodeArray) {
-		// Resize array.
-		nodeArray2 := make([]*Node, count)
-		copy(nodeArray2, nodeArray)
-		nodeArray = nodeArray2
+	if len(removed) == 0 {
+		return false
+	}
+
+	c.setNodes(remaining)
+	for _, n := range removed {
+		n.Close()
 	}
 
-	c.setNodes(nodeArray)
+	return true
 }
 
 func (c *Cluster) nextNodeIndex() int64 {
@@ -61,6 +61,42 @@ func (s *RethinkSuite) TestClusterRecoverAfterNoNodes(c *test.C) {
 	}
 }
 
+func (s *RethinkSuite) TestClusterDetectRemovedNode(c *test.C) {
+	session, err := Connect(ConnectOpts{
+		Addresses:     []string{url, url2},
+		DiscoverHosts: true,
+	})
+	c.Assert(err, test.IsNil)
+	defer session.Close()
+
+	// Wait for the other servers to be discovered
+	deadline := time.Now().Add(30 * time.Second)
+	for len(session.cluster.GetNodes()) < 2 {
+		if time.Now().After(deadline) {
+			c.Fatal("No node was added to the cluster")
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	// Remove a node in the same way as when the server is removed from
+	// rethinkdb.server_status
+	nodes := session.cluster.GetNodes()
+	removed := nodes[0]
+	session.cluster.handleNodeChange(nodeStatus{}, nodeStatus{ID: removed.ID})
+
+	c.Assert(session.cluster.GetNodes(), test.HasLen, len(nodes)-1)
+	for _, node := range session.cluster.GetNodes() {
+		c.Assert(node.ID, test.Not(test.Equals), removed.ID)
+	}
+	c.Assert(removed.Closed(), test.Equals, true)
+
+	// Queries use the remaining nodes
+	var res int
+	err = Expr(1).ReadOne(&res, session)
+	c.Assert(err, test.IsNil)
+	c.Assert(res, test.Equals, 1)
+}
+
 func (s *RethinkSuite) TestClusterNodeHealth(c *test.C) {
 	session, err := Connect(ConnectOpts{
 		Addresses:           []string{url1, url2, url3},
@@ -0,0 +1,291 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// structField describes a single field of a struct which is being generated.
+type structField struct {
+	goName    string
+	name      string
+	kind      string // the predeclared type of the field, or "" for other types
+	omitEmpty bool
+	nonEmpty  string // expression checking that the field is not empty, used with omitempty
+}
+
+// decodeFuncs maps predeclared types to the encoding package function call
+// used to decode them.
+var decodeFuncs = map[string]string{
+	"string":  "DecodeString(val)",
+	"bool":    "DecodeBool(val)",
+	"int":     "DecodeInt(val, 0)",
+	"int8":    "DecodeInt(val, 8)",
+	"int16":   "DecodeInt(val, 16)",
+	"int32":   "DecodeInt(val, 32)",
+	"int64":   "DecodeInt64(val)",
+	"uint":    "DecodeUint(val, 0)",
+	"uint8":   "DecodeUint(val, 8)",
+	"uint16":  "DecodeUint(val, 16)",
+	"uint32":  "DecodeUint(val, 32)",
+	"uint64":  "DecodeUint64(val)",
+	"float32": "DecodeFloat64(val)",
+	"float64": "DecodeFloat64(val)",
+}
+
+// generate returns the formatted source of the encoders and decoders for
+// the named struct types in pkg. Field names are read from the first of
+// tagNames set on each field, or the gorethink tag if tagNames is nil.
+func generate(pkg *ast.Package, typeNames, tagNames []string) ([]byte, error) {
+	specs := map[string]*ast.StructType{}
+	for _, f := range pkg.Files {
+		ast.Inspect(f, func(n ast.Node) bool {
+			if ts, ok := n.(*ast.TypeSpec); ok {
+				if st, ok := ts.Type.(*ast.StructType); ok {
+					specs[ts.Name.Name] = st
+				}
+			}
+			return true
+		})
+	}
+
+	var body bytes.Buffer
+	needStrings := false
+	for _, name := range typeNames {
+		st, ok := specs[name]
+		if !ok {
+			return nil, fmt.Errorf("struct type %s not found", name)
+		}
+		fields, err := structFields(name, st, tagNames)
+		if err != nil {
+			return nil, err
+		}
+
+		writeEncoder(&body, name, fields)
+		writeDecoder(&body, name, fields)
+		needStrings = needStrings || len(fields) > 0
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by gorethink-gen; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", pkg.Name)
+	fmt.Fprintf(&buf, "import (\n")
+	if needStrings {
+		fmt.Fprintf(&buf, "\t\"strings\"\n\n")
+	}
+	fmt.Fprintf(&buf, "\t\"gopkg.in/gorethink/gorethink.v3/encoding\"\n)\n\n")
+
+	fmt.Fprintf(&buf, "func init() {\n")
+	for _, name := range typeNames {
+		fmt.Fprintf(&buf, "\tencoding.RegisterGeneratedCodec((*%s)(nil), encode%sRQL, decode%sRQL)\n", name, name, name)
+	}
+	fmt.Fprintf(&buf, "}\n")
+	buf.Write(body.Bytes())
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %v", err)
+	}
+	return src, nil
+}
+
+// getTag returns the value of the first of tagNames set in tag, matching the
+// behaviour of encoding.Tags.
+func getTag(tag reflect.StructTag, tagNames []string) string {
+	if tagNames == nil {
+		return tag.Get("gorethink")
+	}
+
+	for _, tagName := range tagNames {
+		if v := tag.Get(tagName); v != "" {
+			return v
+		}
+	}
+
+	return ""
+}
+
+func structFields(typeName string, st *ast.StructType, tagNames []string) ([]structField, error) {
+	var fields []structField
+	for _, f := range st.Fields.List {
+		if len(f.Names) == 0 {
+			return nil, fmt.Errorf("%s: embedded fields are not supported", typeName)
+		}
+
+		var tag reflect.StructTag
+		if f.Tag != nil {
+			s, err := strconv.Unquote(f.Tag.Value)
+			if err != nil {
+				return nil, err
+			}
+			tag = reflect.StructTag(s)
+		}
+		if tag.Get("gorethink_ref") != "" {
+			return nil, fmt.Errorf("%s: reference fields are not supported", typeName)
+		}
+
+		name, opts := getTag(tag, tagNames), ""
+		if i := strings.Index(name, ","); i != -1 {
+			name, opts = name[:i], name[i+1:]
+		}
+		if name == "-" {
+			continue
+		}
+		if strings.HasPrefix(name, "[") {
+			return nil, fmt.Errorf("%s: compound key fields are not supported", typeName)
+		}
+
+		for _, ident := range f.Names {
+			if !ident.IsExported() {
+				continue
+			}
+
+			sf := structField{goName: ident.Name, name: name}
+			if sf.name == "" {
+				sf.name = ident.Name
+			}
+			if id, ok := f.Type.(*ast.Ident); ok {
+				if _, ok := decodeFuncs[id.Name]; ok {
+					sf.kind = id.Name
+				}
+			}
+
+			for _, opt := range strings.Split(opts, ",") {
+				switch opt {
+				case "":
+				case "omitempty":
+					sf.omitEmpty = true
+				default:
+					return nil, fmt.Errorf("%s.%s: tag option %q is not supported", typeName, ident.Name, opt)
+				}
+			}
+			if sf.omitEmpty {
+				expr, err := nonEmptyExpr(sf, f.Type)
+				if err != nil {
+					return nil, fmt.Errorf("%s.%s: %v", typeName, ident.Name, err)
+				}
+				sf.nonEmpty = expr
+			}
+
+			fields = append(fields, sf)
+		}
+	}
+
+	return fields, nil
+}
+
+func nonEmptyExpr(f structField, typ ast.Expr) (string, error) {
+	v := "v." + f.goName
+	switch f.kind {
+	case "string":
+		return v + ` != ""`, nil
+	case "bool":
+		return v, nil
+	case "":
+	default:
+		return v + " != 0", nil
+	}
+
+	switch t := typ.(type) {
+	case *ast.ArrayType:
+		if t.Len == nil {
+			return "len(" + v + ") != 0", nil
+		}
+	case *ast.MapType:
+		return "len(" + v + ") != 0", nil
+	case *ast.StarExpr, *ast.InterfaceType:
+		return v + " != nil", nil
+	}
+
+	return "", fmt.Errorf("omitempty is only supported for predeclared, slice, map, pointer and interface types")
+}
+
+func writeEncoder(buf *bytes.Buffer, typeName string, fields []structField) {
+	fmt.Fprintf(buf, "\nfunc encode%sRQL(p interface{}) (interface{}, error) {\n", typeName)
+	fmt.Fprintf(buf, "\tv := p.(*%s)\n", typeName)
+	fmt.Fprintf(buf, "\tm := make(map[string]interface{}, %d)\n", len(fields))
+	for _, f := range fields {
+		if f.omitEmpty {
+			fmt.Fprintf(buf, "\tif %s {\n", f.nonEmpty)
+		}
+		if f.kind != "" {
+			fmt.Fprintf(buf, "\tm[%q] = v.%s\n", f.name, f.goName)
+		} else {
+			fmt.Fprintf(buf, "\tif ev, err := encoding.Encode(v.%s); err != nil {\n", f.goName)
+			fmt.Fprintf(buf, "\t\treturn nil, err\n")
+			fmt.Fprintf(buf, "\t} else {\n")
+			fmt.Fprintf(buf, "\t\tm[%q] = ev\n", f.name)
+			fmt.Fprintf(buf, "\t}\n")
+		}
+		if f.omitEmpty {
+			fmt.Fprintf(buf, "\t}\n")
+		}
+	}
+	fmt.Fprintf(buf, "\treturn m, nil\n")
+	fmt.Fprintf(buf, "}\n")
+}
+
+func writeDecoder(buf *bytes.Buffer, typeName string, fields []structField) {
+	fmt.Fprintf(buf, "\nfunc decode%sRQL(p interface{}, src map[string]interface{}) error {\n", typeName)
+	if len(fields) == 0 {
+		fmt.Fprintf(buf, "\treturn nil\n")
+		fmt.Fprintf(buf, "}\n")
+		return
+	}
+
+	fmt.Fprintf(buf, "\tv := p.(*%s)\n", typeName)
+	fmt.Fprintf(buf, "\tfor k, val := range src {\n")
+
+	// Keys which do not match a field exactly are matched case-insensitively
+	// in the same way as the reflection based decoder.
+	names := make([]string, len(fields))
+	for i, f := range fields {
+		names[i] = strconv.Quote(f.name)
+	}
+	fmt.Fprintf(buf, "\t\tswitch k {\n")
+	fmt.Fprintf(buf, "\t\tcase %s:\n", strings.Join(names, ", "))
+	fmt.Fprintf(buf, "\t\tdefault:\n")
+	fmt.Fprintf(buf, "\t\t\tk = strings.ToLower(k)\n")
+	fmt.Fprintf(buf, "\t\t}\n")
+
+	fmt.Fprintf(buf, "\t\tswitch k {\n")
+	seen := map[string]bool{}
+	for _, f := range fields {
+		seen[f.name] = true
+	}
+	for _, f := range fields {
+		keys := []string{strconv.Quote(f.name)}
+		if lower := strings.ToLower(f.name); !seen[lower] {
+			seen[lower] = true
+			keys = append(keys, strconv.Quote(lower))
+		}
+		sort.Strings(keys)
+
+		fmt.Fprintf(buf, "\t\tcase %s:\n", strings.Join(keys, ", "))
+		if f.kind != "" {
+			fmt.Fprintf(buf, "\t\t\tx, err := encoding.%s\n", decodeFuncs[f.kind])
+			fmt.Fprintf(buf, "\t\t\tif err != nil {\n")
+			fmt.Fprintf(buf, "\t\t\t\treturn err\n")
+			fmt.Fprintf(buf, "\t\t\t}\n")
+			if f.kind == "string" || f.kind == "bool" || f.kind == "int64" || f.kind == "uint64" || f.kind == "float64" {
+				fmt.Fprintf(buf, "\t\t\tv.%s = x\n", f.goName)
+			} else {
+				fmt.Fprintf(buf, "\t\t\tv.%s = %s(x)\n", f.goName, f.kind)
+			}
+		} else {
+			fmt.Fprintf(buf, "\t\t\tif err := encoding.Decode(&v.%s, val); err != nil {\n", f.goName)
+			fmt.Fprintf(buf, "\t\t\t\treturn err\n")
+			fmt.Fprintf(buf, "\t\t\t}\n")
+		}
+	}
+	fmt.Fprintf(buf, "\t\t}\n")
+	fmt.Fprintf(buf, "\t}\n")
+	fmt.Fprintf(buf, "\treturn nil\n")
+	fmt.Fprintf(buf, "}\n")
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+func parsePackage(t *testing.T, src string) *ast.Package {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "user.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &ast.Package{Name: f.Name.Name, Files: map[string]*ast.File{"user.go": f}}
+}
+
+func TestGenerate(t *testing.T) {
+	pkg := parsePackage(t, `package models
+
+type User struct {
+	ID      string            `+"`gorethink:\"id,omitempty\"`"+`
+	Age     int               `+"`gorethink:\"age\"`"+`
+	Tags    []string          `+"`gorethink:\"tags,omitempty\"`"+`
+	Extra   map[string]string
+	ignored bool
+	Skip    bool              `+"`gorethink:\"-\"`"+`
+}
+`)
+
+	src, err := generate(pkg, []string{"User"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := parser.ParseFile(token.NewFileSet(), "", src, 0); err != nil {
+		t.Fatalf("generated code does not parse: %v\n%s", err, src)
+	}
+
+	for _, want := range []string{
+		"encoding.RegisterGeneratedCodec((*User)(nil), encodeUserRQL, decodeUserRQL)",
+		`if v.ID != "" {`,
+		`m["age"] = v.Age`,
+		`if len(v.Tags) != 0 {`,
+		`encoding.Encode(v.Extra)`,
+		`case "Extra", "extra":`,
+		`x, err := encoding.DecodeInt(val, 0)`,
+		`v.Age = int(x)`,
+		`encoding.Decode(&v.Tags, val)`,
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("generated code does not contain %q:\n%s", want, src)
+		}
+	}
+	for _, unwanted := range []string{"ignored", "Skip"} {
+		if strings.Contains(string(src), unwanted) {
+			t.Errorf("generated code contains %q:\n%s", unwanted, src)
+		}
+	}
+}
+
+func TestGenerateUnsupported(t *testing.T) {
+	tests := []struct {
+		src, err string
+	}{
+		{"type T struct{ Base }\ntype Base struct{}", "embedded fields"},
+		{"type T struct{ A []int `gorethink:\"[a,b]\"` }", "compound key"},
+		{"type T struct{ A string `gorethink_ref:\"id\"` }", "reference fields"},
+		{"type T struct{ A string `gorethink:\"a,inline\"` }", `option "inline"`},
+		{"type T struct{ A [2]int `gorethink:\"a,omitempty\"` }", "omitempty"},
+		{"type U struct{}", "not found"},
+	}
+
+	for _, tt := range tests {
+		pkg := parsePackage(t, "package models\n"+tt.src)
+		_, err := generate(pkg, []string{"T"}, nil)
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%s: got error %v, want error containing %q", tt.src, err, tt.err)
+		}
+	}
+}
+
+func TestGenerateTags(t *testing.T) {
+	pkg := parsePackage(t, `package models
+
+type User struct {
+	ID   string `+"`gorethink:\"id\" json:\"user_id\"`"+`
+	Name string `+"`json:\"name\"`"+`
+}
+`)
+
+	src, err := generate(pkg, []string{"User"}, []string{"gorethink", "json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{`m["id"] = v.ID`, `m["name"] = v.Name`} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("generated code does not contain %q:\n%s", want, src)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+// Command gorethink-gen writes encoders and decoders for struct types which do
+// not use reflection, these are registered with the gorethink encoding package
+// using RegisterGeneratedCodec and are used in place of the reflection based
+// codecs.
+//
+// For example given a package containing the type
+//
+//	type User struct {
+//		ID   string   `gorethink:"id,omitempty"`
+//		Name string   `gorethink:"name"`
+//		Tags []string `gorethink:"tags"`
+//	}
+//
+// running
+//
+//	gorethink-gen -type=User
+//
+// in the package directory writes the file user_gorethink.go. Fields with
+// types other than the predeclared string, bool and numeric types are encoded
+// and decoded by calling encoding.Encode and encoding.Decode. Structs with
+// embedded fields or reference and compound key tags are not supported.
+//
+// Field names are read from the gorethink tag. If encoding.Tags is set the
+// same tag names should be passed using the -tags flag, for example
+// -tags=gorethink,json.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	typeNames = flag.String("type", "", "comma-separated list of type names; must be set")
+	output    = flag.String("output", "", "output file name; default <type>_gorethink.go")
+	tagNames  = flag.String("tags", "", "comma-separated list of struct tags to read field names from, in order; must match encoding.Tags; default gorethink")
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage of gorethink-gen:\n")
+	fmt.Fprintf(os.Stderr, "\tgorethink-gen -type T [directory]\n")
+	flag.PrintDefaults()
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("gorethink-gen: ")
+	flag.Usage = usage
+	flag.Parse()
+	if *typeNames == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	dir := "."
+	if args := flag.Args(); len(args) > 0 {
+		dir = args[0]
+	}
+
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, parser.ParseComments)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(pkgs) != 1 {
+		log.Fatalf("expected one package in %s, found %d", dir, len(pkgs))
+	}
+
+	var pkg *ast.Package
+	for _, p := range pkgs {
+		pkg = p
+	}
+
+	types := strings.Split(*typeNames, ",")
+	var tags []string
+	if *tagNames != "" {
+		tags = strings.Split(*tagNames, ",")
+	}
+	src, err := generate(pkg, types, tags)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	outputName := *output
+	if outputName == "" {
+		outputName = filepath.Join(dir, strings.ToLower(types[0])+"_gorethink.go")
+	}
+	if err := ioutil.WriteFile(outputName, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -1,11 +1,14 @@
 package gorethink
 
 import (
+	"bufio"
 	"crypto/tls"
 	"encoding/binary"
 	"encoding/json"
 	"fmt"
+	"math/rand"
 	"net"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -15,6 +18,7 @@ import (
 )
 
 const (
+	queryHeaderLen         = 12
 	respHeaderLen          = 12
 	defaultKeepAlivePeriod = time.Second * 30
 )
@@ -31,72 +35,221 @@ type Response struct {
 	Profile   interface{}               `json:"p"`
 }
 
-// Connection is a connection to a rethinkdb database. Connection is not thread
-// safe and should only be accessed be a single goroutine
+// Connection is a connection to a rethinkdb database. Multiple queries can be
+// run on a connection at the same time, responses are read by a single
+// goroutine and passed to the query with the matching token.
 type Connection struct {
 	net.Conn
 
 	address string
 	opts    *ConnectOpts
+	reader  *bufio.Reader
+	writer  *bufio.Writer
 
 	_       [4]byte
 	mu      sync.Mutex
 	token   int64
 	cursors map[int64]*Cursor
+	pending map[int64][]chan *Response
+	readErr error
 	bad     bool
 	closed  bool
+
+	serverInfo *ServerInfo
+
+	writeMu sync.Mutex
+
+	// hookState is used to call OnDisconnect once for each connection which
+	// was passed to OnConnect, see notifyConnect.
+	hookState int32
 }
 
 // NewConnection creates a new connection to the database server
 func NewConnection(address string, opts *ConnectOpts) (*Connection, error) {
+	c, err := newConnection(address, opts, opts.HandshakeVersion)
+	if err != nil && opts.HandshakeVersion == HandshakeV1_0 && isUnsupportedProtocolError(err) {
+		// Servers older than RethinkDB 2.3 do not support the v1.0 handshake so
+		// reconnect using the v0.4 handshake instead
+		return newConnection(address, opts, HandshakeV0_4)
+	}
+
+	return c, err
+}
+
+func newConnection(address string, opts *ConnectOpts, version HandshakeVersion) (*Connection, error) {
 	var err error
 	c := &Connection{
 		address: address,
 		opts:    opts,
 		cursors: make(map[int64]*Cursor),
-	}
-
-	keepAlivePeriod := defaultKeepAlivePeriod
-	if opts.KeepAlivePeriod > 0 {
-		keepAlivePeriod = opts.KeepAlivePeriod
+		pending: make(map[int64][]chan *Response),
 	}
 
 	// Connect to Server
-	nd := net.Dialer{Timeout: c.opts.Timeout, KeepAlive: keepAlivePeriod}
-	if c.opts.TLSConfig == nil {
-		c.Conn, err = nd.Dial("tcp", address)
-	} else {
-		c.Conn, err = tls.DialWithDialer(&nd, "tcp", address, c.opts.TLSConfig)
-	}
+	c.Conn, err = c.dial()
 	if err != nil {
 		return nil, RQLConnectionError{rqlError(err.Error())}
 	}
 
 	// Send handshake
-	handshake, err := c.handshake(opts.HandshakeVersion)
+	handshake, err := c.handshake(version)
 	if err != nil {
+		c.Conn.Close()
 		return nil, err
 	}
 
+	if timeout := c.handshakeTimeout(); timeout > 0 {
+		c.Conn.SetDeadline(time.Now().Add(timeout))
+	}
 	if err = handshake.Send(); err != nil {
+		c.Conn.Close()
 		return nil, err
 	}
+	c.Conn.SetDeadline(time.Time{})
+
+	c.reader = bufio.NewReaderSize(c.Conn, bufferSize(c.opts.ReadBufferSize))
+	c.writer = bufio.NewWriterSize(c.Conn, bufferSize(c.opts.WriteBufferSize))
+
+	go c.readLoop()
+	c.notifyConnect()
 
 	return c, nil
 }
 
+// dial opens the underlying net.Conn, using the DialFunc from ConnectOpts if
+// one was provided. Otherwise each address the hostname resolves to is tried
+// in turn, see resolveAddresses.
+func (c *Connection) dial() (net.Conn, error) {
+	network := "tcp"
+	if c.opts.Network != "" {
+		network = c.opts.Network
+	}
+
+	if c.opts.DialFunc != nil {
+		conn, err := c.opts.DialFunc(network, c.address)
+		if err != nil {
+			return nil, err
+		}
+
+		return c.wrapTLS(conn)
+	}
+
+	keepAlivePeriod := defaultKeepAlivePeriod
+	if c.opts.KeepAlivePeriod > 0 {
+		keepAlivePeriod = c.opts.KeepAlivePeriod
+	}
+
+	nd := net.Dialer{Timeout: c.opts.Timeout, KeepAlive: keepAlivePeriod}
+
+	proxy, err := c.opts.proxyURL()
+	if err != nil {
+		return nil, err
+	}
+	if proxy != nil && network == "tcp" {
+		conn, err := dialProxy(&nd, proxy, c.address, c.handshakeTimeout())
+		if err != nil {
+			return nil, err
+		}
+
+		return c.wrapTLS(conn)
+	}
+
+	var conn net.Conn
+	for _, address := range c.resolveAddresses(network) {
+		if conn, err = nd.Dial(network, address); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return c.wrapTLS(conn)
+}
+
+// wrapTLS wraps conn in a TLS client connection if TLSConfig is set. If the
+// config has no ServerName the hostname of the connection's address is used
+// to verify the server, as conn may be connected to a resolved IP address.
+func (c *Connection) wrapTLS(conn net.Conn) (net.Conn, error) {
+	if c.opts.TLSConfig == nil {
+		return conn, nil
+	}
+
+	config := c.opts.TLSConfig
+	if config.ServerName == "" {
+		host, _, err := net.SplitHostPort(c.address)
+		if err != nil {
+			host = c.address
+		}
+
+		config = config.Clone()
+		config.ServerName = host
+	}
+
+	tlsConn := tls.Client(conn, config)
+	if timeout := c.handshakeTimeout(); timeout > 0 {
+		tlsConn.SetDeadline(time.Now().Add(timeout))
+	}
+	if err := tlsConn.Handshake(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	tlsConn.SetDeadline(time.Time{})
+
+	return tlsConn, nil
+}
+
+// resolveAddresses resolves the hostname of the connection's address,
+// returning every address in a random order so that connections are spread
+// across all of the addresses returned by DNS. The hostname is resolved each
+// time a connection is opened so that changes to DNS records are followed.
+func (c *Connection) resolveAddresses(network string) []string {
+	host, port, err := net.SplitHostPort(c.address)
+	if err != nil || !strings.HasPrefix(network, "tcp") {
+		return []string{c.address}
+	}
+
+	ips, err := net.LookupHost(host)
+	if err != nil || len(ips) == 0 {
+		// Let the dialer report the error
+		return []string{c.address}
+	}
+
+	start := rand.Intn(len(ips))
+	addresses := make([]string, len(ips))
+	for i := range ips {
+		addresses[i] = net.JoinHostPort(ips[(start+i)%len(ips)], port)
+	}
+
+	return addresses
+}
+
+// handshakeTimeout returns the maximum amount of time the handshake may take.
+func (c *Connection) handshakeTimeout() time.Duration {
+	if c.opts.HandshakeTimeout > 0 {
+		return c.opts.HandshakeTimeout
+	}
+
+	return c.opts.Timeout
+}
+
 // Close closes the underlying net.Conn
 func (c *Connection) Close() error {
 	c.mu.Lock()
-	defer c.mu.Unlock()
 
 	var err error
 
-	if !c.closed {
+	closed := c.closed
+	if !closed {
 		err = c.Conn.Close()
 		c.closed = true
 		c.cursors = make(map[int64]*Cursor)
 	}
+	c.mu.Unlock()
+
+	if !closed {
+		c.notifyDisconnect(nil)
+	}
 
 	return err
 }
@@ -106,6 +259,15 @@ func (c *Connection) Close() error {
 //
 // This function is used internally by Run which should be used for most queries.
 func (c *Connection) Query(ctx context.Context, q Query) (*Response, *Cursor, error) {
+	response, cursor, err := c.query(ctx, q)
+	if err != nil && c != nil {
+		c.notifyQueryError(q, err)
+	}
+
+	return response, cursor, err
+}
+
+func (c *Connection) query(ctx context.Context, q Query) (*Response, *Cursor, error) {
 	if ctx == nil {
 		ctx = c.contextFromConnectionOpts()
 	}
@@ -124,15 +286,20 @@ func (c *Connection) Query(ctx context.Context, q Query) (*Response, *Cursor, er
 	if q.Type == p.Query_START || q.Type == p.Query_NOREPLY_WAIT || q.Type == p.Query_SERVER_INFO {
 		q.Token = c.nextToken()
 	}
-	if q.Type == p.Query_START || q.Type == p.Query_NOREPLY_WAIT {
-		if c.opts.Database != "" {
-			var err error
-			q.Opts["db"], err = DB(c.opts.Database).Build()
-			if err != nil {
-				c.mu.Unlock()
-				return nil, nil, RQLDriverError{rqlError(err.Error())}
-			}
+
+	// Register for the response before sending the query, the read loop
+	// passes responses to queries with the same token in the order the
+	// queries were sent
+	var responses chan *Response
+	if noreply, ok := q.Opts["noreply"]; !ok || !noreply.(bool) {
+		if c.readErr != nil {
+			err := c.readErr
+			c.mu.Unlock()
+			return nil, nil, err
 		}
+
+		responses = make(chan *Response, 1)
+		c.pending[q.Token] = append(c.pending[q.Token], responses)
 	}
 	c.mu.Unlock()
 
@@ -140,42 +307,48 @@ func (c *Connection) Query(ctx context.Context, q Query) (*Response, *Cursor, er
 	var cursor *Cursor
 	var errchan chan error = make(chan error, 1)
 	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				err := panicError(r)
+				Log.Errorf("Error processing response: %s", err)
+				c.markBad()
+				errchan <- err
+			}
+		}()
+
 		err := c.sendQuery(q)
 		if err != nil {
 			errchan <- err
 			return
 		}
 
-		if noreply, ok := q.Opts["noreply"]; ok && noreply.(bool) {
+		if responses == nil {
 			errchan <- nil
 			return
 		}
 
-		for {
-			response, err := c.readResponse()
-			if err != nil {
+		r, ok := <-responses
+		if !ok {
+			// The read loop failed or the query was dropped by dropQuery
+			if err := c.readError(); err != nil {
 				errchan <- err
-				return
-			}
-
-			if response.Token == q.Token {
-				// If this was the requested response process and return
-				response, cursor, err = c.processResponse(ctx, q, response)
-				errchan <- err
-				return
-			} else if _, ok := c.cursors[response.Token]; ok {
-				// If the token is in the cursor cache then process the response
-				c.processResponse(ctx, q, response)
 			} else {
-				putResponse(response)
+				errchan <- errCursorClosed
 			}
+			return
 		}
+
+		response, cursor, err = c.processResponse(ctx, q, r)
+		errchan <- err
 	}()
 
 	select {
 	case err := <-errchan:
 		return response, cursor, err
 	case <-ctx.Done():
+		if responses != nil {
+			c.removePending(q.Token, responses)
+		}
 		if q.Type != p.Query_STOP {
 			stopQuery := newStopQuery(q.Token)
 			c.Query(c.contextFromConnectionOpts(), stopQuery)
@@ -184,14 +357,52 @@ func (c *Connection) Query(ctx context.Context, q Query) (*Response, *Cursor, er
 	}
 }
 
+// noreplyWait sends a NOREPLY_WAIT query and waits for the server to respond.
+func (c *Connection) noreplyWait(ctx context.Context) error {
+	_, _, err := c.Query(ctx, Query{
+		Type: p.Query_NOREPLY_WAIT,
+	})
+
+	return err
+}
+
 type ServerResponse struct {
 	ID   string `gorethink:"id"`
 	Name string `gorethink:"name"`
 }
 
+// ServerInfo contains information about the server a connection is connected
+// to, as returned by the SERVER_INFO query.
+type ServerInfo struct {
+	// ID is the UUID of the server.
+	ID string `gorethink:"id"`
+	// Name is the name of the server.
+	Name string `gorethink:"name"`
+	// Proxy is true if the server is a proxy node.
+	Proxy bool `gorethink:"proxy"`
+}
+
+func (i ServerInfo) serverResponse() ServerResponse {
+	return ServerResponse{ID: i.ID, Name: i.Name}
+}
+
 // Server returns the server name and server UUID being used by a connection.
 func (c *Connection) Server() (ServerResponse, error) {
-	var response ServerResponse
+	info, err := c.ServerInfo()
+	return info.serverResponse(), err
+}
+
+// ServerInfo returns information about the server the connection is connected
+// to. The information is cached after it has been fetched from the server.
+func (c *Connection) ServerInfo() (ServerInfo, error) {
+	c.mu.Lock()
+	info := c.serverInfo
+	c.mu.Unlock()
+	if info != nil {
+		return *info, nil
+	}
+
+	var response ServerInfo
 
 	_, cur, err := c.Query(c.contextFromConnectionOpts(), Query{
 		Type: p.Query_SERVER_INFO,
@@ -208,6 +419,10 @@ func (c *Connection) Server() (ServerResponse, error) {
 		return response, err
 	}
 
+	c.mu.Lock()
+	c.serverInfo = &response
+	c.mu.Unlock()
+
 	return response, nil
 }
 
@@ -219,6 +434,9 @@ func (c *Connection) sendQuery(q Query) error {
 		return RQLDriverError{rqlError(fmt.Sprintf("Error building query: %s", err.Error()))}
 	}
 
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
 	// Set timeout
 	if c.opts.WriteTimeout == 0 {
 		c.Conn.SetWriteDeadline(time.Time{})
@@ -228,8 +446,19 @@ func (c *Connection) sendQuery(q Query) error {
 
 	// Send the JSON encoding of the query itself.
 	if err = c.writeQuery(q.Token, b); err != nil {
-		c.bad = true
-		return RQLConnectionError{rqlError(err.Error())}
+		c.markBad()
+		err = RQLConnectionError{rqlError(err.Error())}
+		c.notifyDisconnect(err)
+		return err
+	}
+
+	if c.tracing() {
+		c.trace(TraceEvent{
+			Token:     q.Token,
+			QueryType: q.Type,
+			Data:      b,
+			Size:      queryHeaderLen + len(b),
+		})
 	}
 
 	return nil
@@ -242,20 +471,132 @@ func (c *Connection) nextToken() int64 {
 	return atomic.AddInt64(&c.token, 1)
 }
 
-// readResponse attempts to read a Response from the server, if no response
-// could be read then an error is returned.
-func (c *Connection) readResponse() (*Response, error) {
-	// Set timeout
-	if c.opts.ReadTimeout == 0 {
-		c.Conn.SetReadDeadline(time.Time{})
+// readLoop reads responses from the server and passes each response to the
+// query waiting for it, until the connection fails or is closed.
+func (c *Connection) readLoop() {
+	defer func() {
+		// A malformed response should only break this connection so close it
+		// and fail any queries using it
+		if r := recover(); r != nil {
+			err := panicError(r)
+			Log.Errorf("Error reading response: %s", err)
+			c.failRead(err)
+			c.Close()
+		}
+	}()
+
+	for {
+		response, err := c.readResponse()
+		if err != nil {
+			c.failRead(err)
+			return
+		}
+
+		c.mu.Lock()
+		waiters := c.pending[response.Token]
+		if len(waiters) == 0 {
+			// Nothing is waiting for the response, for example if the query
+			// timed out
+			c.mu.Unlock()
+			putResponse(response)
+			continue
+		}
+		if len(waiters) == 1 {
+			delete(c.pending, response.Token)
+		} else {
+			c.pending[response.Token] = waiters[1:]
+		}
+		c.mu.Unlock()
+
+		waiters[0] <- response
+	}
+}
+
+// removePending stops the read loop passing responses for token to
+// responses, for example after the query waiting for it timed out. If the
+// read loop has not already taken the channel it is closed so that the
+// goroutine waiting on it returns.
+func (c *Connection) removePending(token int64, responses chan *Response) {
+	c.mu.Lock()
+
+	// The read loop may still be using the old slice so build a new one
+	found := false
+	var waiters []chan *Response
+	for _, w := range c.pending[token] {
+		if w == responses {
+			found = true
+		} else {
+			waiters = append(waiters, w)
+		}
+	}
+	if len(waiters) == 0 {
+		delete(c.pending, token)
 	} else {
-		c.Conn.SetReadDeadline(time.Now().Add(c.opts.ReadTimeout))
+		c.pending[token] = waiters
+	}
+	c.mu.Unlock()
+
+	if found {
+		close(responses)
+	}
+}
+
+// dropQuery stops the query with the given token from receiving any more
+// responses without stopping it on the server, queries waiting for a response
+// fail and later responses are discarded by the read loop.
+func (c *Connection) dropQuery(token int64) {
+	c.mu.Lock()
+	delete(c.cursors, token)
+	waiters := c.pending[token]
+	delete(c.pending, token)
+	c.mu.Unlock()
+
+	for _, responses := range waiters {
+		close(responses)
 	}
+}
 
+// failRead stops the connection being used after the read loop fails, any
+// queries waiting for a response are failed with err.
+func (c *Connection) failRead(err error) {
+	c.mu.Lock()
+	closed := c.closed
+	if closed {
+		err = ErrConnectionClosed
+	}
+	c.bad = true
+	c.readErr = err
+
+	// Fail any queries waiting for a response
+	for token, waiters := range c.pending {
+		for _, responses := range waiters {
+			close(responses)
+		}
+		delete(c.pending, token)
+	}
+	c.mu.Unlock()
+
+	if !closed {
+		c.notifyDisconnect(err)
+	}
+}
+
+// readError returns the error which stopped the read loop.
+func (c *Connection) readError() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.readErr
+}
+
+// readResponse attempts to read a Response from the server, if no response
+// could be read then an error is returned. Read timeouts are handled by the
+// context of each query rather than a read deadline as the connection may be
+// idle while waiting for changefeed results.
+func (c *Connection) readResponse() (*Response, error) {
 	// Read response header (token+length)
 	headerBuf := [respHeaderLen]byte{}
 	if _, err := c.read(headerBuf[:], respHeaderLen); err != nil {
-		c.bad = true
 		return nil, RQLConnectionError{rqlError(err.Error())}
 	}
 
@@ -266,18 +607,26 @@ func (c *Connection) readResponse() (*Response, error) {
 	b := make([]byte, int(messageLength))
 
 	if _, err := c.read(b, int(messageLength)); err != nil {
-		c.bad = true
 		return nil, RQLConnectionError{rqlError(err.Error())}
 	}
 
 	// Decode the response
 	var response = newCachedResponse()
 	if err := json.Unmarshal(b, response); err != nil {
-		c.bad = true
 		return nil, RQLDriverError{rqlError(err.Error())}
 	}
 	response.Token = responseToken
 
+	if c.tracing() {
+		c.trace(TraceEvent{
+			Received:     true,
+			Token:        responseToken,
+			ResponseType: response.Type,
+			Data:         b,
+			Size:         respHeaderLen + len(b),
+		})
+	}
+
 	return response, nil
 }
 
@@ -346,6 +695,8 @@ func (c *Connection) processPartialResponse(ctx context.Context, q Query, respon
 		// Create a new cursor if needed
 		cursor = newCursor(ctx, c, cursorType, response.Token, q.Term, q.Opts)
 		cursor.profile = response.Profile
+		cursor.prefetchBatches = q.prefetchBatches
+		cursor.disablePrefetch = q.disablePrefetch
 
 		c.cursors[response.Token] = cursor
 	}
@@ -381,6 +732,28 @@ func (c *Connection) processWaitResponse(q Query, response *Response) (*Response
 	return response, nil, nil
 }
 
+// openCursors returns the cursors which are waiting for more results from the
+// server.
+func (c *Connection) openCursors() []*Cursor {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cursors := make([]*Cursor, 0, len(c.cursors))
+	for _, cursor := range c.cursors {
+		cursors = append(cursors, cursor)
+	}
+
+	return cursors
+}
+
+// markBad marks the connection as bad so that it is not reused.
+func (c *Connection) markBad() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.bad = true
+}
+
 func (c *Connection) isBad() bool {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -146,6 +146,7 @@ func (c *connectionHandshakeV1_0) Send() error {
 
 	// Check server nonce
 	if !strings.HasPrefix(serverNonce, clientNonce) {
+		c.conn.Close()
 		return RQLAuthError{RQLDriverError{rqlError("Invalid nonce from server")}}
 	}
 
@@ -175,7 +176,7 @@ func (c *connectionHandshakeV1_0) writeFirstMessage(clientNonce string) error {
 		username = c.conn.opts.Username
 	}
 
-	c.authMsg = fmt.Sprintf("n=%s,r=%s", username, clientNonce)
+	c.authMsg = fmt.Sprintf("n=%s,r=%s", scramEscapeUsername(username), clientNonce)
 	msg := fmt.Sprintf(
 		`{"protocol_version": %d,"authentication": "n,,%s","authentication_method": "%s"}`,
 		handshakeV1_0_protocolVersionNumber, c.authMsg, handshakeV1_0_authenticationMethod,
@@ -198,6 +199,23 @@ func (c *connectionHandshakeV1_0) writeFirstMessage(clientNonce string) error {
 	return c.writeData(data)
 }
 
+// scramEscapeUsername escapes the characters which are not allowed in SCRAM
+// usernames as described in RFC 5802.
+func scramEscapeUsername(username string) string {
+	username = strings.Replace(username, "=", "=3D", -1)
+	return strings.Replace(username, ",", "=2C", -1)
+}
+
+// isUnsupportedProtocolError returns true if the error was returned by a server
+// which does not support the handshake version sent by the driver.
+func isUnsupportedProtocolError(err error) bool {
+	if err, ok := err.(RQLConnectionError); ok {
+		return strings.Contains(err.Error(), "unsupported protocol version")
+	}
+
+	return false
+}
+
 func (c *connectionHandshakeV1_0) checkServerVersions() error {
 	b, err := c.readResponse()
 	if err != nil {
@@ -2,9 +2,23 @@ package gorethink
 
 import (
 	"encoding/binary"
+	"io"
+
 	"golang.org/x/net/context"
 )
 
+// defaultBufferSize is the size of the read and write buffers used by each
+// connection if no size is set in ConnectOpts.
+const defaultBufferSize = 32 * 1024
+
+func bufferSize(size int) int {
+	if size <= 0 {
+		return defaultBufferSize
+	}
+
+	return size
+}
+
 // Write 'data' to conn
 func (c *Connection) writeData(data []byte) error {
 	_, err := c.Conn.Write(data[:])
@@ -12,35 +26,30 @@ func (c *Connection) writeData(data []byte) error {
 	return err
 }
 
+// read reads exactly length bytes into buf from the connection's read buffer.
 func (c *Connection) read(buf []byte, length int) (total int, err error) {
-	var n int
-	for total < length {
-		if n, err = c.Conn.Read(buf[total:length]); err != nil {
-			break
-		}
-		total += n
-	}
-
-	return total, err
+	return io.ReadFull(c.reader, buf[:length])
 }
 
+// writeQuery writes the query header and body to the connection's write
+// buffer and flushes it.
 func (c *Connection) writeQuery(token int64, q []byte) error {
-	pos := 0
-	dataLen := 8 + 4 + len(q)
-	data := make([]byte, dataLen)
+	var header [queryHeaderLen]byte
 
-	// Send the protocol version to the server as a 4-byte little-endian-encoded integer
-	binary.LittleEndian.PutUint64(data[pos:], uint64(token))
-	pos += 8
+	// Send the token as an 8-byte little-endian-encoded integer
+	binary.LittleEndian.PutUint64(header[:], uint64(token))
 
-	// Send the length of the auth key to the server as a 4-byte little-endian-encoded integer
-	binary.LittleEndian.PutUint32(data[pos:], uint32(len(q)))
-	pos += 4
+	// Send the length of the query as a 4-byte little-endian-encoded integer
+	binary.LittleEndian.PutUint32(header[8:], uint32(len(q)))
 
-	// Send the auth key as an ASCII string
-	pos += copy(data[pos:], q)
+	if _, err := c.writer.Write(header[:]); err != nil {
+		return err
+	}
+	if _, err := c.writer.Write(q); err != nil {
+		return err
+	}
 
-	return c.writeData(data)
+	return c.writer.Flush()
 }
 
 func (c *Connection) contextFromConnectionOpts() context.Context {
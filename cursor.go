@@ -4,8 +4,11 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"reflect"
 	"sync"
+	"time"
 
 	"golang.org/x/net/context"
 	"gopkg.in/gorethink/gorethink.v3/encoding"
@@ -17,6 +20,12 @@ var (
 	errCursorClosed = errors.New("connection closed, cannot read cursor")
 )
 
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+var reqlTypeKey = []byte(`"$reql_type$"`)
+
+const defaultPrefetchBatches = 1
+
 func newCursor(ctx context.Context, conn *Connection, cursorType string, token int64, term *Term, opts map[string]interface{}) *Cursor {
 	if cursorType == "" {
 		cursorType = "Cursor"
@@ -38,10 +47,27 @@ func newCursor(ctx context.Context, conn *Connection, cursorType string, token i
 		responses:  make([]json.RawMessage, 0),
 		ctx:        ctx,
 	}
+	cursor.fetchCond = sync.NewCond(&cursor.mu)
 
 	return cursor
 }
 
+// Results is the interface implemented by Cursor containing the methods used to
+// read the results of a query. Functions which accept Results instead of a
+// *Cursor can be passed test doubles or wrappers which do not require a
+// connection to the database.
+type Results interface {
+	Next(dest interface{}) bool
+	All(result interface{}) error
+	One(result interface{}) error
+	Err() error
+	Close() error
+	Profile() interface{}
+	IsNil() bool
+}
+
+var _ Results = (*Cursor)(nil)
+
 // Cursor is the result of a query. Its cursor starts before the first row
 // of the result set. A Cursor is not thread safe and should only be accessed
 // by a single goroutine at any given time. Use Next to advance through the
@@ -67,8 +93,10 @@ type Cursor struct {
 	term       *Term
 	opts       map[string]interface{}
 	ctx        context.Context
+	cancel     context.CancelFunc
 
 	mu            sync.RWMutex
+	fetchCond     *sync.Cond
 	lastErr       error
 	fetching      bool
 	closed        bool
@@ -78,7 +106,29 @@ type Cursor struct {
 	pendingSkips  int
 	buffer        []interface{}
 	responses     []json.RawMessage
+	batchSizes    []int
 	profile       interface{}
+	stats         CursorStats
+	decoder       *encoding.Decoder
+
+	// prefetchBatches and disablePrefetch are set from the query's run options
+	prefetchBatches int
+	disablePrefetch bool
+}
+
+// CursorStats contains counters describing how a cursor's results have been
+// streamed from the server.
+type CursorStats struct {
+	// Documents is the number of documents read from the cursor.
+	Documents int64
+	// Batches is the number of batches of documents received from the server.
+	Batches int64
+	// Bytes is the total size of the raw JSON documents received.
+	Bytes int64
+	// Continues is the number of continue queries sent to fetch more batches.
+	Continues int64
+	// FetchTime is the cumulative round-trip time of all continue queries.
+	FetchTime time.Duration
 }
 
 // Profile returns the information returned from the query profiler.
@@ -93,7 +143,20 @@ func (c *Cursor) Profile() interface{} {
 	return c.profile
 }
 
-// Type returns the cursor type (by default "Cursor")
+// Stats returns a snapshot of the cursor's statistics.
+func (c *Cursor) Stats() CursorStats {
+	if c == nil {
+		return CursorStats{}
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.stats
+}
+
+// Type returns the cursor type (by default "Cursor"). Changefeeds return one
+// of "Feed", "AtomFeed", "OrderByLimitFeed", "UnionedFeed" or "IncludesFeed".
 func (c *Cursor) Type() string {
 	if c == nil {
 		return "Cursor"
@@ -105,6 +168,12 @@ func (c *Cursor) Type() string {
 	return c.cursorType
 }
 
+// IsFeed returns true if the cursor is reading from a changefeed. Feeds do not
+// finish unless closed so methods such as All will block indefinitely.
+func (c *Cursor) IsFeed() bool {
+	return c.Type() != "Cursor"
+}
+
 // Err returns nil if no errors happened during iteration, or the actual
 // error otherwise.
 func (c *Cursor) Err() error {
@@ -118,9 +187,27 @@ func (c *Cursor) Err() error {
 	return c.lastErr
 }
 
+// CursorCloseOpts allows calls to the CloseWithOpts function to be configured.
+type CursorCloseOpts struct {
+	// WaitForAck waits for the server to acknowledge that the query has been
+	// stopped, even if the context used to run the query has been cancelled,
+	// and for any batches being fetched in the background to be returned.
+	WaitForAck bool
+	// Force closes the cursor without stopping the query on the server or
+	// waiting for any response. Responses for the query which arrive later are
+	// discarded, other queries using the same connection are not affected.
+	Force bool
+}
+
 // Close closes the cursor, preventing further enumeration. If the end is
 // encountered, the cursor is closed automatically. Close is idempotent.
 func (c *Cursor) Close() error {
+	return c.CloseWithOpts(CursorCloseOpts{})
+}
+
+// CloseWithOpts closes the cursor in the same way as Close but allows the
+// behaviour to be configured.
+func (c *Cursor) CloseWithOpts(opts CursorCloseOpts) error {
 	if c == nil {
 		return errNilCursor
 	}
@@ -128,6 +215,11 @@ func (c *Cursor) Close() error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	// Release the query's timeout, if any
+	if c.cancel != nil {
+		defer c.cancel()
+	}
+
 	var err error
 
 	// If cursor is already closed return immediately
@@ -146,9 +238,24 @@ func (c *Cursor) Close() error {
 		return nil
 	}
 
-	// Stop any unfinished queries
-	if !c.finished {
-		_, _, err = conn.Query(c.ctx, newStopQuery(c.token))
+	if opts.Force {
+		conn.dropQuery(c.token)
+	} else if !c.finished {
+		// Stop any unfinished queries
+		ctx := c.ctx
+		if opts.WaitForAck || ctx == nil || ctx.Err() != nil {
+			ctx = conn.contextFromConnectionOpts()
+		}
+
+		_, _, err = conn.Query(ctx, newStopQuery(c.token))
+
+		// Once the query has been stopped the server responds to any
+		// outstanding continue queries
+		if opts.WaitForAck && err == nil {
+			for c.fetching {
+				c.fetchCond.Wait()
+			}
+		}
 	}
 
 	if c.releaseConn != nil {
@@ -161,6 +268,7 @@ func (c *Cursor) Close() error {
 	c.conn = nil
 	c.buffer = nil
 	c.responses = nil
+	c.batchSizes = nil
 
 	return err
 }
@@ -203,6 +311,30 @@ func (c *Cursor) Next(dest interface{}) bool {
 	return hasMore
 }
 
+// NextErr behaves the same as Next but also returns any error encountered
+// while fetching or decoding the document, removing the need to call Err.
+//
+// NextErr returns false and a nil error at the end of the result set.
+//
+//     var response interface{}
+//     for {
+//         ok, err := cursor.NextErr(&response)
+//         if err != nil {
+//             ...
+//         }
+//         if !ok {
+//             break
+//         }
+//         ...
+//     }
+func (c *Cursor) NextErr(dest interface{}) (bool, error) {
+	if c.Next(dest) {
+		return true, nil
+	}
+
+	return false, c.Err()
+}
+
 func (c *Cursor) nextLocked(dest interface{}, progressCursor bool) (bool, error) {
 	for {
 		if err := c.seekCursor(true); err != nil {
@@ -223,10 +355,15 @@ func (c *Cursor) nextLocked(dest interface{}, progressCursor bool) (bool, error)
 				c.buffer = c.buffer[1:]
 			}
 
-			err := encoding.Decode(dest, data)
+			err := c.decodeDocument(dest, data)
 			if err != nil {
 				return false, err
 			}
+			if progressCursor {
+				c.stats.Documents++
+			}
+
+			c.prefetchLocked()
 
 			return true, nil
 		}
@@ -335,6 +472,9 @@ func (c *Cursor) nextResponseLocked() ([]byte, bool, error) {
 		if len(c.responses) > 0 {
 			var response json.RawMessage
 			response, c.responses = c.responses[0], c.responses[1:]
+			c.consumeResponsesLocked(1)
+			c.stats.Documents++
+			c.prefetchLocked()
 
 			return []byte(response), true, nil
 		}
@@ -352,6 +492,25 @@ func (c *Cursor) nextResponseLocked() ([]byte, bool, error) {
 // to reuse the existing slice without allocating any more space by either
 // resizing or returning a selection of the slice if necessary.
 func (c *Cursor) All(result interface{}) error {
+	return c.allN(result, -1)
+}
+
+// AllN retrieves at most limit documents from the result set into the provided
+// slice and closes the cursor, stopping the query on the server if there are
+// more documents remaining. This can be used to safely read a sample of
+// documents from a changefeed or a large table.
+//
+// The result argument must necessarily be the address for a slice, as with All.
+func (c *Cursor) AllN(result interface{}, limit int) error {
+	if limit < 0 {
+		limit = 0
+	}
+
+	return c.allN(result, limit)
+}
+
+// allN implements All and AllN, a negative limit reads all documents.
+func (c *Cursor) allN(result interface{}, limit int) error {
 	if c == nil {
 		return errNilCursor
 	}
@@ -364,7 +523,7 @@ func (c *Cursor) All(result interface{}) error {
 	slicev = slicev.Slice(0, slicev.Cap())
 	elemt := slicev.Type().Elem()
 	i := 0
-	for {
+	for limit < 0 || i < limit {
 		if slicev.Len() == i {
 			elemp := reflect.New(elemt)
 			if !c.Next(elemp.Interface()) {
@@ -426,6 +585,238 @@ func (c *Cursor) One(result interface{}) error {
 	return nil
 }
 
+// WriteTo writes all documents from the result set to w as a JSON array and
+// closes the cursor. Documents are written as they are read from the server
+// using the raw JSON of each response, so the full result set is never held
+// in memory.
+//
+// WriteTo implements the io.WriterTo interface.
+func (c *Cursor) WriteTo(w io.Writer) (n int64, err error) {
+	if c == nil {
+		return 0, errNilCursor
+	}
+	defer func() {
+		if cerr := c.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	write := func(b []byte) error {
+		m, err := w.Write(b)
+		n += int64(m)
+		return err
+	}
+
+	first := true
+	writeRow := func(row []byte) error {
+		sep := []byte(",")
+		if first {
+			sep = []byte("[")
+			first = false
+		}
+		if err := write(sep); err != nil {
+			return err
+		}
+		return write(row)
+	}
+
+	// Any documents which have already been decoded (for example after calling
+	// Peek) need to be re-encoded before reading the remaining responses
+	rows, err := c.takeBuffer()
+	if err != nil {
+		return n, err
+	}
+	for _, row := range rows {
+		if err = writeRow(row); err != nil {
+			return n, err
+		}
+	}
+
+	for {
+		b, ok := c.NextResponse()
+		if !ok {
+			break
+		}
+
+		// If the result is a single array then it can be written as is
+		if first && c.isAtomArray(b) {
+			if err = write(b); err != nil {
+				return n, err
+			}
+			return n, c.Err()
+		}
+
+		if err = writeRow(b); err != nil {
+			return n, err
+		}
+	}
+
+	if err = c.Err(); err != nil {
+		return n, err
+	}
+
+	if first {
+		if err = write([]byte("[")); err != nil {
+			return n, err
+		}
+	}
+	err = write([]byte("]"))
+
+	return n, err
+}
+
+// Reader returns an io.Reader which reads all documents from the result set
+// as newline-delimited JSON, one document per line. Documents are fetched from
+// the server as the reader is consumed and the cursor is closed once all
+// documents have been read.
+func (c *Cursor) Reader() io.Reader {
+	return &cursorReader{cursor: c}
+}
+
+type cursorReader struct {
+	cursor  *Cursor
+	started bool
+	rows    [][]byte
+	buf     []byte
+	err     error
+}
+
+func (r *cursorReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		r.err = r.next()
+	}
+
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+
+	return n, nil
+}
+
+// next loads the next document into the read buffer, the returned error is
+// returned once the buffer has been consumed.
+func (r *cursorReader) next() error {
+	c := r.cursor
+	if c == nil {
+		return errNilCursor
+	}
+
+	if !r.started {
+		r.started = true
+
+		rows, err := c.takeBuffer()
+		if err != nil {
+			c.Close()
+			return err
+		}
+		r.rows = rows
+	}
+
+	for len(r.rows) == 0 {
+		b, ok := c.NextResponse()
+		if !ok {
+			if err := c.Err(); err != nil {
+				c.Close()
+				return err
+			}
+			if err := c.Close(); err != nil {
+				return err
+			}
+			return io.EOF
+		}
+
+		// Atoms containing an array are split into one document per line
+		if c.isAtomArray(b) {
+			var rows []json.RawMessage
+			if err := json.Unmarshal(b, &rows); err != nil {
+				c.Close()
+				return err
+			}
+			for _, row := range rows {
+				r.rows = append(r.rows, row)
+			}
+		} else {
+			r.rows = append(r.rows, b)
+		}
+	}
+
+	r.buf = append(append(r.buf[:0], r.rows[0]...), '\n')
+	r.rows = r.rows[1:]
+
+	return nil
+}
+
+// BinaryReader returns an io.Reader which reads the binary data returned by a
+// query which returns a single binary object, such as a field containing a
+// file. The cursor is closed once the data has been read.
+//
+// RethinkDB returns binary objects in a single response so the data is held in
+// memory while it is read.
+func (c *Cursor) BinaryReader() io.Reader {
+	return &binaryReader{cursor: c}
+}
+
+type binaryReader struct {
+	cursor *Cursor
+	r      *bytes.Reader
+	err    error
+}
+
+func (r *binaryReader) Read(p []byte) (int, error) {
+	if r.r == nil {
+		if r.err != nil {
+			return 0, r.err
+		}
+
+		var b []byte
+		if r.err = r.cursor.One(&b); r.err != nil {
+			return 0, r.err
+		}
+		r.r = bytes.NewReader(b)
+	}
+
+	return r.r.Read(p)
+}
+
+// takeBuffer removes any decoded documents from the buffer and returns them
+// encoded as JSON.
+func (c *Cursor) takeBuffer() ([][]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.applyPendingSkips(true)
+
+	rows := make([][]byte, len(c.buffer))
+	for i, v := range c.buffer {
+		if raw, ok := v.(rawDocument); ok {
+			rows[i] = raw
+			continue
+		}
+
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		rows[i] = b
+	}
+	c.buffer = c.buffer[:0]
+	c.stats.Documents += int64(len(rows))
+
+	return rows, nil
+}
+
+// isAtomArray returns true if the response is an atom containing an array.
+func (c *Cursor) isAtomArray(response []byte) bool {
+	c.mu.RLock()
+	isAtom := c.isAtom
+	c.mu.RUnlock()
+
+	response = bytes.TrimSpace(response)
+	return isAtom && len(response) > 0 && response[0] == '['
+}
+
 // Interface retrieves all documents from the result set and returns the data
 // as an interface{} and closes the cursor.
 //
@@ -498,6 +889,89 @@ func (c *Cursor) Listen(channel interface{}) {
 	}()
 }
 
+// Each iterates over the result set calling fn with the raw JSON of each
+// response, in the same form as returned by NextResponse. Iteration stops when
+// the end of the result set is reached, fn returns false or fn returns an
+// error. The cursor is always closed when Each returns.
+//
+//     err := cursor.Each(func(row json.RawMessage) (bool, error) {
+//         ...
+//         return true, nil
+//     })
+func (c *Cursor) Each(fn func(row json.RawMessage) (bool, error)) error {
+	if c == nil {
+		return errNilCursor
+	}
+
+	for {
+		b, ok := c.NextResponse()
+		if !ok {
+			break
+		}
+
+		cont, err := fn(json.RawMessage(b))
+		if err != nil {
+			c.Close()
+			return err
+		}
+		if !cont {
+			break
+		}
+	}
+
+	if err := c.Err(); err != nil {
+		c.Close()
+		return err
+	}
+
+	return c.Close()
+}
+
+// EachValue behaves like Each however each row is decoded before fn is called.
+// The function must have the signature func(T) (bool, error), the type that
+// each row is decoded into is determined by the type of the argument.
+//
+//     err := cursor.EachValue(func(doc MyDocumentType) (bool, error) {
+//         ...
+//         return true, nil
+//     })
+func (c *Cursor) EachValue(fn interface{}) error {
+	if c == nil {
+		return errNilCursor
+	}
+
+	fnv := reflect.ValueOf(fn)
+	fnt := fnv.Type()
+	if fnt.Kind() != reflect.Func || fnt.NumIn() != 1 || fnt.NumOut() != 2 ||
+		fnt.Out(0).Kind() != reflect.Bool || fnt.Out(1) != errorType {
+		panic("fn argument must be a function of type func(T) (bool, error)")
+	}
+
+	elemt := fnt.In(0)
+	for {
+		elemp := reflect.New(elemt)
+		if !c.Next(elemp.Interface()) {
+			break
+		}
+
+		out := fnv.Call([]reflect.Value{elemp.Elem()})
+		if err, _ := out[1].Interface().(error); err != nil {
+			c.Close()
+			return err
+		}
+		if !out[0].Bool() {
+			break
+		}
+	}
+
+	if err := c.Err(); err != nil {
+		c.Close()
+		return err
+	}
+
+	return c.Close()
+}
+
 // IsNil tests if the current row is nil.
 func (c *Cursor) IsNil() bool {
 	if c == nil {
@@ -508,6 +982,9 @@ func (c *Cursor) IsNil() bool {
 	defer c.mu.RUnlock()
 
 	if len(c.buffer) > 0 {
+		if raw, ok := c.buffer[0].(rawDocument); ok {
+			return bytes.Equal(bytes.TrimSpace(raw), []byte("null"))
+		}
 		return c.buffer[0] == nil
 	}
 
@@ -529,8 +1006,8 @@ func (c *Cursor) IsNil() bool {
 
 // fetchMore fetches more rows from the database.
 //
-// If wait is true then it will wait for the database to reply otherwise it
-// will return after sending the continue query.
+// If a batch is already being fetched in the background then fetchMore waits
+// for that request to complete instead of sending another continue query.
 func (c *Cursor) fetchMore() error {
 	var err error
 
@@ -541,19 +1018,155 @@ func (c *Cursor) fetchMore() error {
 			return errCursorClosed
 		}
 
-		q := Query{
-			Type:  p.Query_CONTINUE,
-			Token: c.token,
-		}
-
 		c.mu.Unlock()
-		_, _, err = c.conn.Query(c.ctx, q)
+		start := time.Now()
+		err = c.continueQuery(c.conn)
 		c.mu.Lock()
+		c.recordFetchLocked(time.Since(start))
+	} else {
+		c.fetchCond.Wait()
+		err = c.lastErr
+	}
+
+	return err
+}
+
+// continueQuery sends a continue query for the cursor and waits for the
+// response. If ConnectOpts.FetchTimeout is set and the server does not respond
+// in time then ErrFetchTimeout is returned.
+func (c *Cursor) continueQuery(conn *Connection) error {
+	ctx := c.ctx
+	timeout := c.connOpts.FetchTimeout
+	if timeout > 0 {
+		if ctx == nil {
+			ctx = context.Background()
+		}
+
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	_, _, err := conn.Query(ctx, Query{
+		Type:  p.Query_CONTINUE,
+		Token: c.token,
+	})
+	if err == ErrQueryTimeout && timeout > 0 && (c.ctx == nil || c.ctx.Err() == nil) {
+		return ErrFetchTimeout
 	}
 
 	return err
 }
 
+// prefetchLocked starts fetching the next batch in the background once only
+// one response remains before the last PrefetchBatches-1 buffered batches, so
+// by default the next batch is fetched when one buffered response remains.
+// The PrefetchBatches and DisablePrefetch run options take precedence over the
+// connection options.
+func (c *Cursor) prefetchLocked() {
+	if c.disablePrefetch || c.connOpts.DisablePrefetch {
+		return
+	}
+	if c.fetching || c.finished || c.closed || c.conn == nil || c.lastErr != nil {
+		return
+	}
+
+	prefetchBatches := c.prefetchBatches
+	if prefetchBatches <= 0 {
+		prefetchBatches = c.connOpts.PrefetchBatches
+	}
+	if prefetchBatches <= 0 {
+		prefetchBatches = defaultPrefetchBatches
+	}
+
+	remaining := len(c.responses)
+	for i := len(c.batchSizes) - 1; i >= 0 && i >= len(c.batchSizes)-(prefetchBatches-1); i-- {
+		remaining -= c.batchSizes[i]
+	}
+	if remaining > 1 {
+		return
+	}
+	if c.bufferFullLocked() {
+		return
+	}
+
+	c.fetching = true
+	go c.prefetch(c.conn)
+}
+
+// bufferFullLocked returns true if the cursor is holding at least as many
+// documents or bytes as allowed by ConnectOpts.MaxBufferedDocuments and
+// ConnectOpts.MaxBufferedBytes. Once full no more batches are prefetched until
+// the buffered documents have been read.
+func (c *Cursor) bufferFullLocked() bool {
+	if max := c.connOpts.MaxBufferedDocuments; max > 0 {
+		if len(c.buffer)+len(c.responses) >= max {
+			return true
+		}
+	}
+
+	if max := c.connOpts.MaxBufferedBytes; max > 0 {
+		// Documents which have already been decoded are not counted
+		size := 0
+		for _, v := range c.buffer {
+			if doc, ok := v.(rawDocument); ok {
+				size += len(doc)
+				if size >= max {
+					return true
+				}
+			}
+		}
+		for _, response := range c.responses {
+			size += len(response)
+			if size >= max {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// prefetch sends a continue query for the cursor, the response is added to
+// the cursor by the connection when it is received.
+func (c *Cursor) prefetch(conn *Connection) {
+	start := time.Now()
+	err := c.continueQuery(conn)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.recordFetchLocked(time.Since(start))
+
+	if err != nil {
+		if !c.closed {
+			c.handleErrorLocked(err)
+		}
+		c.fetching = false
+		c.fetchCond.Broadcast()
+	}
+}
+
+// recordFetchLocked records the round-trip time of a continue query.
+func (c *Cursor) recordFetchLocked(d time.Duration) {
+	c.stats.Continues++
+	c.stats.FetchTime += d
+}
+
+// consumeResponsesLocked records that n responses have been removed from
+// the front of the responses queue.
+func (c *Cursor) consumeResponsesLocked(n int) {
+	for n > 0 && len(c.batchSizes) > 0 {
+		if c.batchSizes[0] > n {
+			c.batchSizes[0] -= n
+			return
+		}
+
+		n -= c.batchSizes[0]
+		c.batchSizes = c.batchSizes[1:]
+	}
+}
+
 // handleError sets the value of lastErr to err if lastErr is not yet set.
 func (c *Cursor) handleError(err error) error {
 	c.mu.Lock()
@@ -580,11 +1193,21 @@ func (c *Cursor) extend(response *Response) {
 
 func (c *Cursor) extendLocked(response *Response) {
 	c.responses = append(c.responses, response.Responses...)
+	if len(response.Responses) > 0 {
+		c.batchSizes = append(c.batchSizes, len(response.Responses))
+	}
+	c.stats.Batches++
+	for _, r := range response.Responses {
+		c.stats.Bytes += int64(len(r))
+	}
 	c.finished = response.Type != p.Response_SUCCESS_PARTIAL
 	c.fetching = false
 	c.isAtom = response.Type == p.Response_SUCCESS_ATOM
 
 	putResponse(response)
+
+	c.fetchCond.Broadcast()
+	c.prefetchLocked()
 }
 
 // seekCursor takes care of loading more data if needed and applying pending skips
@@ -648,18 +1271,28 @@ func (c *Cursor) applyPendingSkips(drainFromBuffer bool) (stillPending bool) {
 
 	if len(c.responses) > c.pendingSkips {
 		c.responses = c.responses[c.pendingSkips:]
+		c.consumeResponsesLocked(c.pendingSkips)
 		c.pendingSkips = 0
 		return false
 	}
 
 	c.pendingSkips -= len(c.responses)
+	c.consumeResponsesLocked(len(c.responses))
 	c.responses = c.responses[:0]
 	return c.pendingSkips > 0
 }
 
+// rawDocument is a document which has been buffered by the cursor but not
+// yet decoded, documents are only decoded once they are read from the cursor.
+type rawDocument json.RawMessage
+
 // bufferResponse reads a single response and stores the result into the buffer
 // if the response is from an atomic response, it will check if the
-// response contains multiple records and store them all into the buffer
+// response contains multiple records and store them all into the buffer.
+//
+// Responses are not decoded until they are read unless the response is an
+// atom containing pseudo-types, as these may need converting before the number
+// of documents is known (for example GROUPED_DATA).
 func (c *Cursor) bufferNextResponse() error {
 	if c.closed {
 		return errCursorClosed
@@ -671,22 +1304,44 @@ func (c *Cursor) bufferNextResponse() error {
 
 	response := c.responses[0]
 	c.responses = c.responses[1:]
+	c.consumeResponsesLocked(1)
 
-	var value interface{}
-	decoder := json.NewDecoder(bytes.NewBuffer(response))
-	if c.connOpts.UseJSONNumber {
-		decoder.UseNumber()
-	}
-	err := decoder.Decode(&value)
-	if err != nil {
-		return err
+	if c.isAtom && bytes.Contains(response, reqlTypeKey) {
+		value, err := c.decodeResponse(response)
+		if err != nil {
+			return err
+		}
+
+		c.bufferValue(value)
+		return nil
 	}
 
-	value, err = recursivelyConvertPseudotype(value, c.opts)
-	if err != nil {
-		return err
+	trimmed := bytes.TrimSpace(response)
+	if c.isAtom && len(trimmed) > 0 && trimmed[0] == '[' {
+		var data []json.RawMessage
+		if err := json.Unmarshal(trimmed, &data); err != nil {
+			return err
+		}
+
+		for _, v := range data {
+			c.buffer = append(c.buffer, rawDocument(v))
+		}
+	} else if bytes.Equal(trimmed, []byte("null")) {
+		c.buffer = append(c.buffer, nil)
+	} else {
+		c.buffer = append(c.buffer, rawDocument(response))
+
+		// If this is the only value in the response and the response was an
+		// atom then set the single value flag
+		if c.isAtom {
+			c.isSingleValue = true
+		}
 	}
+	return nil
+}
 
+// bufferValue stores an already decoded response into the buffer.
+func (c *Cursor) bufferValue(value interface{}) {
 	// If response is an ATOM then try and convert to an array
 	if data, ok := value.([]interface{}); ok && c.isAtom {
 		c.buffer = append(c.buffer, data...)
@@ -701,5 +1356,94 @@ func (c *Cursor) bufferNextResponse() error {
 			c.isSingleValue = true
 		}
 	}
-	return nil
+}
+
+// decodeDocument decodes a buffered document into dest. Documents which have
+// not yet been decoded are copied as is when dest is a *json.RawMessage.
+//
+// The cursor keeps its own decoder so that the decoding plan for each
+// destination type is only resolved once per cursor.
+func (c *Cursor) decodeDocument(dest interface{}, data interface{}) error {
+	if c.decoder == nil {
+		c.decoder = encoding.NewDecoder()
+	}
+
+	raw, ok := data.(rawDocument)
+	if !ok {
+		return c.decoder.Decode(dest, data)
+	}
+
+	if dest, ok := dest.(*json.RawMessage); ok {
+		*dest = append((*dest)[:0], raw...)
+		return nil
+	}
+
+	value, err := c.decodeResponse(raw)
+	if err != nil {
+		return err
+	}
+
+	return c.decoder.Decode(dest, value)
+}
+
+// numberFormat returns the format used to decode numbers in responses, the
+// number_format run option takes precedence over the connection options.
+func (c *Cursor) numberFormat() (string, error) {
+	numberFormat := "native"
+	if c.connOpts.NumberFormat != "" {
+		numberFormat = c.connOpts.NumberFormat
+	} else if c.connOpts.UseJSONNumber {
+		numberFormat = "json"
+	}
+	if opt, ok := c.opts["number_format"]; ok {
+		sopt, ok := opt.(string)
+		if !ok {
+			return "", fmt.Errorf("Invalid number_format run option \"%v\".", opt)
+		}
+		numberFormat = sopt
+	}
+
+	switch numberFormat {
+	case "native", "json", "int64":
+		return numberFormat, nil
+	default:
+		return "", fmt.Errorf("Unknown number_format run option \"%s\".", numberFormat)
+	}
+}
+
+// decodeResponse decodes a raw JSON response, converting any pseudo-types
+// to their native Go types. Responses which do not contain pseudo-types are
+// not walked.
+func (c *Cursor) decodeResponse(response []byte) (value interface{}, err error) {
+	defer func() {
+		// Stop the connection being reused after a malformed response
+		if r := recover(); r != nil {
+			err = panicError(r)
+			if c.conn != nil {
+				c.conn.markBad()
+			}
+		}
+	}()
+
+	numberFormat, err := c.numberFormat()
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(response))
+	if numberFormat != "native" {
+		decoder.UseNumber()
+	}
+	if err = decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	if numberFormat == "int64" {
+		value = normalizeNumbers(value, true)
+	}
+
+	if !bytes.Contains(response, reqlTypeKey) {
+		return value, nil
+	}
+
+	return recursivelyConvertPseudotype(value, c.opts)
 }
@@ -1,10 +1,19 @@
 package gorethink
 
 import (
+	"bufio"
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
 	"time"
 
+	"golang.org/x/net/context"
 	test "gopkg.in/check.v1"
+	p "gopkg.in/gorethink/gorethink.v3/ql2"
+	"gopkg.in/gorethink/gorethink.v3/types"
 )
 
 type object struct {
@@ -166,6 +175,32 @@ func (s *RethinkSuite) TestCursorStructPseudoTypes(c *test.C) {
 	c.Assert(response.B, jsonEquals, []byte("hello"))
 }
 
+func (s *RethinkSuite) TestCursorNullFieldsMock(c *test.C) {
+	mock := NewMock()
+	mock.On(Table("test")).Return([]interface{}{
+		map[string]interface{}{"name": "a", "age": nil},
+	}, nil)
+
+	var res struct {
+		Name  types.NullString `gorethink:"name,omitempty"`
+		Age   types.NullInt64  `gorethink:"age,omitempty"`
+		Email types.NullString `gorethink:"email,omitempty"`
+	}
+	cursor, err := Table("test").Run(mock)
+	c.Assert(err, test.IsNil)
+	c.Assert(cursor.One(&res), test.IsNil)
+
+	c.Assert(res.Name, test.Equals, types.NullString{String: "a", Valid: true, Present: true})
+	c.Assert(res.Age, test.Equals, types.NullInt64{Valid: false, Present: true})
+	c.Assert(res.Email, test.Equals, types.NullString{Valid: false, Present: false})
+	mock.AssertExpectations(c)
+
+	// Missing fields are omitted, null fields which were present are kept
+	b, err := Expr(res).MarshalQuery()
+	c.Assert(err, test.IsNil)
+	c.Assert(string(b), test.Equals, `{"age":null,"name":"a"}`)
+}
+
 func (s *RethinkSuite) TestCursorAtomString(c *test.C) {
 	res, err := Expr("a").Run(session)
 	c.Assert(err, test.IsNil)
@@ -358,12 +393,677 @@ func (s *RethinkSuite) TestCursorChangesClose(c *test.C) {
 	res, err := DB("test").Table("Table3").Changes().Run(session)
 	c.Assert(err, test.IsNil)
 	c.Assert(res, test.NotNil)
+	c.Assert(res.IsFeed(), test.Equals, true)
 
 	// Ensure that the cursor can be closed
 	err = res.Close()
 	c.Assert(err, test.IsNil)
 }
 
+func (s *RethinkSuite) TestCursorChangesCloseWaitForAck(c *test.C) {
+	// Ensure table + database exist
+	DBCreate("test").Exec(session)
+	DB("test").TableDrop("Table3").Exec(session)
+	DB("test").TableCreate("Table3").Exec(session)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	res, err := DB("test").Table("Table3").Changes().Run(session, RunOpts{
+		Context: ctx,
+	})
+	c.Assert(err, test.IsNil)
+
+	// The query should be stopped even though the context has been cancelled
+	cancel()
+	err = res.CloseWithOpts(CursorCloseOpts{WaitForAck: true})
+	c.Assert(err, test.IsNil)
+}
+
+func (s *RethinkSuite) TestCursorCloseForce(c *test.C) {
+	res, err := Range(1000).Run(session, RunOpts{
+		MaxBatchRows: 10,
+	})
+	c.Assert(err, test.IsNil)
+
+	err = res.CloseWithOpts(CursorCloseOpts{Force: true})
+	c.Assert(err, test.IsNil)
+
+	var response int
+	c.Assert(res.Next(&response), test.Equals, false)
+
+	// The session should still be usable after the query is dropped
+	var n int
+	err = Expr(1).ReadOne(&n, session)
+	c.Assert(err, test.IsNil)
+	c.Assert(n, test.Equals, 1)
+}
+
+func (s *RethinkSuite) TestCursorCloseForceSharedConn(c *test.C) {
+	// The server reads queries but never responds
+	client, server := net.Pipe()
+	defer client.Close()
+	go io.Copy(ioutil.Discard, server)
+
+	conn := &Connection{
+		Conn:    client,
+		opts:    &ConnectOpts{},
+		writer:  bufio.NewWriter(client),
+		cursors: make(map[int64]*Cursor),
+		pending: make(map[int64][]chan *Response),
+	}
+	cursor := newCursor(nil, conn, "", 1, nil, nil)
+	other := newCursor(nil, conn, "", 2, nil, nil)
+	conn.cursors[1] = cursor
+	conn.cursors[2] = other
+
+	// A batch is being fetched when the cursor is closed
+	errs := make(chan error, 1)
+	go func() {
+		_, _, err := conn.Query(nil, Query{Type: p.Query_CONTINUE, Token: 1})
+		errs <- err
+	}()
+	for deadline := time.Now().Add(time.Second); ; {
+		conn.mu.Lock()
+		n := len(conn.pending[1])
+		conn.mu.Unlock()
+		if n > 0 {
+			break
+		}
+		c.Assert(time.Now().Before(deadline), test.Equals, true)
+		time.Sleep(time.Millisecond)
+	}
+
+	c.Assert(cursor.CloseWithOpts(CursorCloseOpts{Force: true}), test.IsNil)
+	select {
+	case err := <-errs:
+		c.Assert(err, test.NotNil)
+	case <-time.After(time.Second):
+		c.Fatal("fetch was not stopped")
+	}
+
+	// Other queries using the connection are not affected
+	c.Assert(conn.isBad(), test.Equals, false)
+	conn.mu.Lock()
+	c.Assert(conn.cursors, test.HasLen, 1)
+	c.Assert(conn.cursors[2], test.Equals, other)
+	c.Assert(conn.pending, test.HasLen, 0)
+	conn.mu.Unlock()
+}
+
+func (s *RethinkSuite) TestCursorFetchTimeout(c *test.C) {
+	session, err := Connect(ConnectOpts{
+		Address:      url,
+		FetchTimeout: 100 * time.Millisecond,
+	})
+	c.Assert(err, test.IsNil)
+	defer session.Close()
+
+	// Ensure table + database exist
+	DBCreate("test").Exec(session)
+	DB("test").TableDrop("Table3").Exec(session)
+	DB("test").TableCreate("Table3").Exec(session)
+
+	// No changes are made so the feed should time out
+	res, err := DB("test").Table("Table3").Changes().Run(session)
+	c.Assert(err, test.IsNil)
+
+	var response interface{}
+	c.Assert(res.Next(&response), test.Equals, false)
+	c.Assert(res.Err(), test.Equals, ErrFetchTimeout)
+}
+
+func (s *RethinkSuite) TestCursorPrefetch(c *test.C) {
+	session, err := Connect(ConnectOpts{
+		Address:         url,
+		PrefetchBatches: 3,
+	})
+	c.Assert(err, test.IsNil)
+	defer session.Close()
+
+	res, err := Range(1000).Run(session, RunOpts{
+		MaxBatchRows: 10,
+	})
+	c.Assert(err, test.IsNil)
+
+	var response []int
+	err = res.All(&response)
+	c.Assert(err, test.IsNil)
+	c.Assert(response, test.HasLen, 1000)
+	for i, v := range response {
+		c.Assert(v, test.Equals, i)
+	}
+}
+
+func (s *RethinkSuite) TestCursorDisablePrefetch(c *test.C) {
+	session, err := Connect(ConnectOpts{
+		Address:         url,
+		DisablePrefetch: true,
+	})
+	c.Assert(err, test.IsNil)
+	defer session.Close()
+
+	res, err := Range(1000).Run(session, RunOpts{
+		MaxBatchRows: 10,
+	})
+	c.Assert(err, test.IsNil)
+
+	var response []int
+	err = res.All(&response)
+	c.Assert(err, test.IsNil)
+	c.Assert(response, test.HasLen, 1000)
+}
+
+// newPrefetchTestCursor returns a cursor holding a partial batch of three
+// responses, the connection accepts continue queries but never responds.
+func newPrefetchTestCursor(opts *ConnectOpts, prefetchBatches int, disablePrefetch bool) *Cursor {
+	client, server := net.Pipe()
+	go io.Copy(ioutil.Discard, server)
+
+	opts.ReadTimeout = time.Second
+	conn := &Connection{
+		Conn:    client,
+		opts:    opts,
+		writer:  bufio.NewWriter(client),
+		cursors: make(map[int64]*Cursor),
+		pending: make(map[int64][]chan *Response),
+	}
+	cursor := newCursor(nil, conn, "", 1, nil, nil)
+	cursor.prefetchBatches = prefetchBatches
+	cursor.disablePrefetch = disablePrefetch
+	cursor.extend(&Response{
+		Type:      p.Response_SUCCESS_PARTIAL,
+		Responses: []json.RawMessage{[]byte("1"), []byte("2"), []byte("3")},
+	})
+
+	return cursor
+}
+
+func (s *RethinkSuite) TestCursorPrefetchOneResponseRemaining(c *test.C) {
+	cursor := newPrefetchTestCursor(&ConnectOpts{}, 0, false)
+	c.Assert(cursor.fetching, test.Equals, false)
+
+	var v int
+	c.Assert(cursor.Next(&v), test.Equals, true)
+	c.Assert(cursor.fetching, test.Equals, false)
+
+	// The next batch is fetched once one buffered response remains
+	c.Assert(cursor.Next(&v), test.Equals, true)
+	c.Assert(cursor.fetching, test.Equals, true)
+}
+
+func (s *RethinkSuite) TestCursorPrefetchRunOpts(c *test.C) {
+	// Two batches are kept buffered so the next batch is fetched immediately
+	cursor := newPrefetchTestCursor(&ConnectOpts{}, 2, false)
+	c.Assert(cursor.fetching, test.Equals, true)
+
+	// The run option takes precedence over the connection options
+	cursor = newPrefetchTestCursor(&ConnectOpts{PrefetchBatches: 2}, 1, false)
+	c.Assert(cursor.fetching, test.Equals, false)
+
+	cursor = newPrefetchTestCursor(&ConnectOpts{}, 0, true)
+	var v int
+	c.Assert(cursor.Next(&v), test.Equals, true)
+	c.Assert(cursor.Next(&v), test.Equals, true)
+	c.Assert(cursor.fetching, test.Equals, false)
+}
+
+func (s *RethinkSuite) TestCursorMaxBufferedDocuments(c *test.C) {
+	session, err := Connect(ConnectOpts{
+		Address:              url,
+		PrefetchBatches:      10,
+		MaxBufferedDocuments: 20,
+	})
+	c.Assert(err, test.IsNil)
+	defer session.Close()
+
+	res, err := Range(1000).Run(session, RunOpts{
+		MaxBatchRows: 10,
+	})
+	c.Assert(err, test.IsNil)
+
+	var response []int
+	err = res.All(&response)
+	c.Assert(err, test.IsNil)
+	c.Assert(response, test.HasLen, 1000)
+}
+
+func (s *RethinkSuite) TestCursorBufferFull(c *test.C) {
+	cursor := newCursor(nil, nil, "", 0, nil, nil)
+	cursor.connOpts = &ConnectOpts{MaxBufferedDocuments: 2}
+	cursor.responses = []json.RawMessage{[]byte("1")}
+	c.Assert(cursor.bufferFullLocked(), test.Equals, false)
+	cursor.responses = append(cursor.responses, []byte("2"))
+	c.Assert(cursor.bufferFullLocked(), test.Equals, true)
+
+	cursor.connOpts = &ConnectOpts{MaxBufferedBytes: 4}
+	cursor.responses = []json.RawMessage{[]byte(`"a"`)}
+	c.Assert(cursor.bufferFullLocked(), test.Equals, false)
+	cursor.responses = append(cursor.responses, []byte(`"b"`))
+	c.Assert(cursor.bufferFullLocked(), test.Equals, true)
+
+	// Documents of the current batch which have not been decoded are counted
+	cursor.responses = []json.RawMessage{[]byte(`"a"`)}
+	cursor.buffer = []interface{}{"decoded", rawDocument(`"b"`)}
+	c.Assert(cursor.bufferFullLocked(), test.Equals, true)
+	cursor.buffer = cursor.buffer[:1]
+	c.Assert(cursor.bufferFullLocked(), test.Equals, false)
+}
+
+func (s *RethinkSuite) TestCursorNumberFormat(c *test.C) {
+	response := []byte(`[{"id":9007199254740993,"score":1.5,"at":{"$reql_type$":"TIME","epoch_time":1,"timezone":"+00:00"}}]`)
+
+	cursor := newCursor(nil, nil, "", 0, nil, map[string]interface{}{"number_format": "int64"})
+	value, err := cursor.decodeResponse(response)
+	c.Assert(err, test.IsNil)
+	doc := value.([]interface{})[0].(map[string]interface{})
+	c.Assert(doc["id"], test.Equals, int64(9007199254740993))
+	c.Assert(doc["score"], test.Equals, 1.5)
+	c.Assert(doc["at"].(time.Time).Equal(time.Unix(1, 0)), test.Equals, true)
+
+	cursor = newCursor(nil, nil, "", 0, nil, map[string]interface{}{"number_format": "json"})
+	value, err = cursor.decodeResponse(response)
+	c.Assert(err, test.IsNil)
+	doc = value.([]interface{})[0].(map[string]interface{})
+	c.Assert(doc["id"], test.Equals, json.Number("9007199254740993"))
+
+	// The run option takes precedence over the connection options
+	cursor = newCursor(nil, nil, "", 0, nil, map[string]interface{}{"number_format": "native"})
+	cursor.connOpts = &ConnectOpts{NumberFormat: "int64"}
+	value, err = cursor.decodeResponse(response)
+	c.Assert(err, test.IsNil)
+	doc = value.([]interface{})[0].(map[string]interface{})
+	c.Assert(doc["id"], test.Equals, float64(9007199254740993))
+
+	cursor = newCursor(nil, nil, "", 0, nil, nil)
+	cursor.connOpts = &ConnectOpts{UseJSONNumber: true}
+	value, err = cursor.decodeResponse(response)
+	c.Assert(err, test.IsNil)
+	doc = value.([]interface{})[0].(map[string]interface{})
+	c.Assert(doc["id"], test.Equals, json.Number("9007199254740993"))
+
+	cursor = newCursor(nil, nil, "", 0, nil, map[string]interface{}{"number_format": "string"})
+	_, err = cursor.decodeResponse(response)
+	c.Assert(err, test.ErrorMatches, `Unknown number_format run option "string".`)
+
+	// Client side options are not sent to the server
+	q := Query{Opts: map[string]interface{}{"number_format": "int64", "profile": true}}
+	c.Assert(q.Build(), jsonEquals, []interface{}{0, map[string]interface{}{"profile": true}})
+}
+
+func (s *RethinkSuite) TestCursorEach(c *test.C) {
+	res, err := Range(5).Run(session)
+	c.Assert(err, test.IsNil)
+
+	var response []string
+	err = res.Each(func(row json.RawMessage) (bool, error) {
+		response = append(response, string(row))
+		return true, nil
+	})
+	c.Assert(err, test.IsNil)
+	c.Assert(response, test.DeepEquals, []string{"0", "1", "2", "3", "4"})
+}
+
+func (s *RethinkSuite) TestCursorEachStop(c *test.C) {
+	res, err := Range(5).Run(session)
+	c.Assert(err, test.IsNil)
+
+	var count int
+	err = res.Each(func(row json.RawMessage) (bool, error) {
+		count++
+		return count < 2, nil
+	})
+	c.Assert(err, test.IsNil)
+	c.Assert(count, test.Equals, 2)
+	c.Assert(res.Next(new(interface{})), test.Equals, false)
+}
+
+func (s *RethinkSuite) TestCursorEachValueMock(c *test.C) {
+	mock := NewMock()
+	mock.On(Table("test")).Return([]interface{}{
+		map[string]interface{}{"id": 1, "name": "Object 1"},
+		map[string]interface{}{"id": 2, "name": "Object 2"},
+		map[string]interface{}{"id": 3, "name": "Object 3"},
+	}, nil)
+
+	res, err := Table("test").Run(mock)
+	c.Assert(err, test.IsNil)
+
+	var response []object
+	err = res.EachValue(func(o object) (bool, error) {
+		response = append(response, o)
+		return o.ID < 2, nil
+	})
+	c.Assert(err, test.IsNil)
+	c.Assert(response, test.DeepEquals, []object{
+		object{ID: 1, Name: "Object 1"},
+		object{ID: 2, Name: "Object 2"},
+	})
+	mock.AssertExpectations(c)
+}
+
+func (s *RethinkSuite) TestCursorEachValueMockError(c *test.C) {
+	mock := NewMock()
+	mock.On(Table("test")).Return([]interface{}{1, 2, 3}, nil)
+
+	res, err := Table("test").Run(mock)
+	c.Assert(err, test.IsNil)
+
+	err = res.EachValue(func(i int) (bool, error) {
+		return true, fmt.Errorf("Expected error")
+	})
+	c.Assert(err, test.NotNil)
+	c.Assert(err.Error(), test.Equals, "Expected error")
+	mock.AssertExpectations(c)
+}
+
+func (s *RethinkSuite) TestCursorWriteTo(c *test.C) {
+	res, err := Range(5).Run(session)
+	c.Assert(err, test.IsNil)
+
+	buf := &bytes.Buffer{}
+	n, err := res.WriteTo(buf)
+	c.Assert(err, test.IsNil)
+	c.Assert(n, test.Equals, int64(buf.Len()))
+	c.Assert(buf.String(), test.Equals, "[0,1,2,3,4]")
+}
+
+func (s *RethinkSuite) TestCursorWriteToAtom(c *test.C) {
+	res, err := Expr([]interface{}{1, 2, 3}).Run(session)
+	c.Assert(err, test.IsNil)
+
+	buf := &bytes.Buffer{}
+	_, err = res.WriteTo(buf)
+	c.Assert(err, test.IsNil)
+	c.Assert(buf.Bytes(), jsonEquals, []byte("[1,2,3]"))
+}
+
+func (s *RethinkSuite) TestCursorWriteToMock(c *test.C) {
+	mock := NewMock()
+	mock.On(Table("test")).Return([]interface{}{
+		map[string]interface{}{"id": 1},
+		map[string]interface{}{"id": 2},
+	}, nil)
+
+	res, err := Table("test").Run(mock)
+	c.Assert(err, test.IsNil)
+
+	buf := &bytes.Buffer{}
+	_, err = res.WriteTo(buf)
+	c.Assert(err, test.IsNil)
+	c.Assert(buf.String(), test.Equals, `[{"id":1},{"id":2}]`)
+	mock.AssertExpectations(c)
+}
+
+func (s *RethinkSuite) TestCursorWriteToMockEmpty(c *test.C) {
+	mock := NewMock()
+	mock.On(Table("test")).Return([]interface{}{}, nil)
+
+	res, err := Table("test").Run(mock)
+	c.Assert(err, test.IsNil)
+
+	buf := &bytes.Buffer{}
+	_, err = res.WriteTo(buf)
+	c.Assert(err, test.IsNil)
+	c.Assert(buf.String(), test.Equals, "[]")
+	mock.AssertExpectations(c)
+}
+
+func (s *RethinkSuite) TestCursorReader(c *test.C) {
+	res, err := Range(3).Run(session)
+	c.Assert(err, test.IsNil)
+
+	b, err := ioutil.ReadAll(res.Reader())
+	c.Assert(err, test.IsNil)
+	c.Assert(string(b), test.Equals, "0\n1\n2\n")
+	c.Assert(res.IsNil(), test.Equals, true)
+}
+
+func (s *RethinkSuite) TestCursorReaderAtom(c *test.C) {
+	res, err := Expr([]interface{}{1, 2, 3}).Run(session)
+	c.Assert(err, test.IsNil)
+
+	b, err := ioutil.ReadAll(res.Reader())
+	c.Assert(err, test.IsNil)
+	c.Assert(string(b), test.Equals, "1\n2\n3\n")
+}
+
+func (s *RethinkSuite) TestCursorReaderMock(c *test.C) {
+	mock := NewMock()
+	mock.On(Table("test")).Return([]interface{}{
+		map[string]interface{}{"id": 1},
+		map[string]interface{}{"id": 2},
+	}, nil)
+
+	res, err := Table("test").Run(mock)
+	c.Assert(err, test.IsNil)
+
+	b, err := ioutil.ReadAll(res.Reader())
+	c.Assert(err, test.IsNil)
+	c.Assert(string(b), test.Equals, "{\"id\":1}\n{\"id\":2}\n")
+	mock.AssertExpectations(c)
+}
+
+func (s *RethinkSuite) TestCursorReaderMockError(c *test.C) {
+	mock := NewMock()
+	mock.On(Table("test")).Return(nil, fmt.Errorf("An error occurred"))
+
+	res, err := Table("test").Run(mock)
+	c.Assert(err, test.NotNil)
+
+	_, err = ioutil.ReadAll(res.Reader())
+	c.Assert(err, test.Equals, errNilCursor)
+}
+
+func (s *RethinkSuite) TestCursorBinaryReaderMock(c *test.C) {
+	mock := NewMock()
+	mock.On(Table("files").Get("a").Field("data")).Return([]interface{}{[]byte("hello")}, nil)
+
+	res, err := Table("files").Get("a").Field("data").Run(mock)
+	c.Assert(err, test.IsNil)
+
+	b, err := ioutil.ReadAll(res.BinaryReader())
+	c.Assert(err, test.IsNil)
+	c.Assert(string(b), test.Equals, "hello")
+	mock.AssertExpectations(c)
+}
+
+func (s *RethinkSuite) TestCursorBinaryReaderMockError(c *test.C) {
+	mock := NewMock()
+	mock.On(Table("files").Get("a").Field("data")).Return(nil, fmt.Errorf("An error occurred"))
+
+	res, err := Table("files").Get("a").Field("data").Run(mock)
+	c.Assert(err, test.NotNil)
+
+	_, err = ioutil.ReadAll(res.BinaryReader())
+	c.Assert(err, test.Equals, errNilCursor)
+}
+
+func (s *RethinkSuite) TestCursorStats(c *test.C) {
+	res, err := Range(100).Run(session, RunOpts{MaxBatchRows: 10})
+	c.Assert(err, test.IsNil)
+
+	var n int
+	for res.Next(&n) {
+	}
+	c.Assert(res.Err(), test.IsNil)
+
+	stats := res.Stats()
+	c.Assert(stats.Documents, test.Equals, int64(100))
+	c.Assert(stats.Batches >= 10, test.Equals, true)
+	c.Assert(stats.Continues, test.Equals, stats.Batches-1)
+	c.Assert(stats.Bytes > 0, test.Equals, true)
+	c.Assert(stats.FetchTime > 0, test.Equals, true)
+}
+
+func (s *RethinkSuite) TestCursorStatsMock(c *test.C) {
+	mock := NewMock()
+	mock.On(Table("test")).Return([]interface{}{1, 2, 3}, nil)
+
+	res, err := Table("test").Run(mock)
+	c.Assert(err, test.IsNil)
+
+	var n int
+	c.Assert(res.Next(&n), test.Equals, true)
+	c.Assert(res.Stats().Documents, test.Equals, int64(1))
+
+	var all []int
+	c.Assert(res.All(&all), test.IsNil)
+	c.Assert(res.Stats().Documents, test.Equals, int64(3))
+	mock.AssertExpectations(c)
+}
+
+func (s *RethinkSuite) TestCursorIsFeedMock(c *test.C) {
+	mock := NewMock()
+	mock.On(Table("test")).Return([]interface{}{1, 2, 3}, nil)
+
+	res, err := Table("test").Run(mock)
+	c.Assert(err, test.IsNil)
+	c.Assert(res.Type(), test.Equals, "Cursor")
+	c.Assert(res.IsFeed(), test.Equals, false)
+
+	var nilCursor *Cursor
+	c.Assert(nilCursor.IsFeed(), test.Equals, false)
+	mock.AssertExpectations(c)
+}
+
+func newTestCursor(isAtom bool, responses ...string) *Cursor {
+	cursor := newCursor(nil, nil, "", 0, nil, nil)
+	cursor.finished = true
+	cursor.isAtom = isAtom
+	for _, response := range responses {
+		cursor.responses = append(cursor.responses, json.RawMessage(response))
+	}
+
+	return cursor
+}
+
+func (s *RethinkSuite) TestCursorLazyDecodePseudotypes(c *test.C) {
+	cursor := newTestCursor(false,
+		`{"id":1,"t":{"$reql_type$":"TIME","epoch_time":1,"timezone":"+00:00"}}`,
+		`{"id":2}`,
+	)
+
+	type doc struct {
+		ID int       `gorethink:"id"`
+		T  time.Time `gorethink:"t"`
+	}
+
+	var d doc
+	c.Assert(cursor.Next(&d), test.Equals, true)
+	c.Assert(d.ID, test.Equals, 1)
+	c.Assert(d.T.Unix(), test.Equals, int64(1))
+
+	var raw json.RawMessage
+	c.Assert(cursor.Next(&raw), test.Equals, true)
+	c.Assert(string(raw), test.Equals, `{"id":2}`)
+
+	c.Assert(cursor.Next(&d), test.Equals, false)
+	c.Assert(cursor.Err(), test.IsNil)
+}
+
+func (s *RethinkSuite) TestCursorLazyDecodeAtom(c *test.C) {
+	cursor := newTestCursor(true, `[1,null,3]`)
+
+	c.Assert(cursor.IsNil(), test.Equals, false)
+
+	var response []interface{}
+	c.Assert(cursor.All(&response), test.IsNil)
+	c.Assert(response, test.DeepEquals, []interface{}{float64(1), nil, float64(3)})
+}
+
+func (s *RethinkSuite) TestCursorLazyDecodeGroupedData(c *test.C) {
+	cursor := newTestCursor(true, `{"$reql_type$":"GROUPED_DATA","data":[["a",1],["b",2]]}`)
+
+	var response []map[string]interface{}
+	c.Assert(cursor.All(&response), test.IsNil)
+	c.Assert(response, test.DeepEquals, []map[string]interface{}{
+		{"group": "a", "reduction": float64(1)},
+		{"group": "b", "reduction": float64(2)},
+	})
+}
+
+func (s *RethinkSuite) TestCursorLazyDecodeMalformedPseudotype(c *test.C) {
+	// The time is missing its epoch_time field
+	cursor := newTestCursor(false, `{"$reql_type$":"TIME","timezone":"+00:00"}`)
+
+	var response interface{}
+	ok, err := cursor.NextErr(&response)
+	c.Assert(ok, test.Equals, false)
+	c.Assert(err, test.NotNil)
+}
+
+func (s *RethinkSuite) TestCursorNextErrMock(c *test.C) {
+	mock := NewMock()
+	mock.On(Table("test")).Return([]interface{}{1, "a"}, nil)
+
+	res, err := Table("test").Run(mock)
+	c.Assert(err, test.IsNil)
+
+	var n int
+	ok, err := res.NextErr(&n)
+	c.Assert(err, test.IsNil)
+	c.Assert(ok, test.Equals, true)
+	c.Assert(n, test.Equals, 1)
+
+	ok, err = res.NextErr(&n)
+	c.Assert(err, test.NotNil)
+	c.Assert(ok, test.Equals, false)
+	mock.AssertExpectations(c)
+}
+
+func (s *RethinkSuite) TestCursorNextErrEnd(c *test.C) {
+	cursor := newTestCursor(false, `1`)
+
+	var n int
+	ok, err := cursor.NextErr(&n)
+	c.Assert(err, test.IsNil)
+	c.Assert(ok, test.Equals, true)
+
+	ok, err = cursor.NextErr(&n)
+	c.Assert(err, test.IsNil)
+	c.Assert(ok, test.Equals, false)
+
+	var nilCursor *Cursor
+	_, err = nilCursor.NextErr(&n)
+	c.Assert(err, test.Equals, errNilCursor)
+}
+
+func (s *RethinkSuite) TestCursorAllN(c *test.C) {
+	res, err := Range(1000).Run(session, RunOpts{
+		MaxBatchRows: 10,
+	})
+	c.Assert(err, test.IsNil)
+
+	var response []int
+	err = res.AllN(&response, 15)
+	c.Assert(err, test.IsNil)
+	c.Assert(response, test.HasLen, 15)
+	c.Assert(response[14], test.Equals, 14)
+	c.Assert(res.Next(&response), test.Equals, false)
+}
+
+func (s *RethinkSuite) TestCursorAllNMock(c *test.C) {
+	mock := NewMock()
+	mock.On(Table("test")).Return([]interface{}{1, 2, 3}, nil).Times(3)
+
+	var response []int
+
+	res, err := Table("test").Run(mock)
+	c.Assert(err, test.IsNil)
+	c.Assert(res.AllN(&response, 2), test.IsNil)
+	c.Assert(response, test.DeepEquals, []int{1, 2})
+
+	res, err = Table("test").Run(mock)
+	c.Assert(err, test.IsNil)
+	c.Assert(res.AllN(&response, 5), test.IsNil)
+	c.Assert(response, test.DeepEquals, []int{1, 2, 3})
+
+	res, err = Table("test").Run(mock)
+	c.Assert(err, test.IsNil)
+	c.Assert(res.AllN(&response, 0), test.IsNil)
+	c.Assert(response, test.HasLen, 0)
+	mock.AssertExpectations(c)
+}
+
 func (s *RethinkSuite) TestCursorReuseResult(c *test.C) {
 	// Test query
 	query := Expr([]interface{}{
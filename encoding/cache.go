@@ -6,6 +6,7 @@ import (
 	"reflect"
 	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -22,6 +23,7 @@ type field struct {
 	quoted        bool
 	reference     bool
 	refName       string
+	nanos         bool
 	compound      bool
 	compoundIndex int
 }
@@ -135,8 +137,12 @@ func typeFields(t reflect.Type) []field {
 					ft = ft.Elem()
 				}
 
+				// Fields with the inline option are flattened into the parent
+				// in the same way as untagged anonymous struct fields.
+				inline := sf.Anonymous && name == "" || opts.Contains("inline")
+
 				// Record found field and index sequence.
-				if name != "" || !sf.Anonymous || ft.Kind() != reflect.Struct || isPseudoType(ft) {
+				if !inline || ft.Kind() != reflect.Struct || isPseudoType(ft) {
 					tagged := name != ""
 					if name == "" {
 						name = sf.Name
@@ -149,6 +155,7 @@ func typeFields(t reflect.Type) []field {
 						omitEmpty:     opts.Contains("omitempty"),
 						reference:     opts.Contains("reference"),
 						refName:       ref,
+						nanos:         opts.Contains("nanos") && ft == timeType,
 						compound:      isCompound,
 						compoundIndex: compoundIndex,
 					}))
@@ -253,15 +260,15 @@ func dominantField(fields []field) (field, bool) {
 }
 
 var fieldCache struct {
-	sync.RWMutex
-	m map[reflect.Type][]field
+	value atomic.Value // map[reflect.Type][]field
+	mu    sync.Mutex   // used only by writers
 }
 
 // cachedTypeFields is like typeFields but uses a cache to avoid repeated work.
+// The cache is shared by the encoder and decoder, reads do not take a lock.
 func cachedTypeFields(t reflect.Type) []field {
-	fieldCache.RLock()
-	f := fieldCache.m[t]
-	fieldCache.RUnlock()
+	m, _ := fieldCache.value.Load().(map[reflect.Type][]field)
+	f := m[t]
 	if f != nil {
 		return f
 	}
@@ -273,11 +280,15 @@ func cachedTypeFields(t reflect.Type) []field {
 		f = []field{}
 	}
 
-	fieldCache.Lock()
-	if fieldCache.m == nil {
-		fieldCache.m = map[reflect.Type][]field{}
+	// Copy the existing map so that readers never see a partial update.
+	fieldCache.mu.Lock()
+	m, _ = fieldCache.value.Load().(map[reflect.Type][]field)
+	newM := make(map[reflect.Type][]field, len(m)+1)
+	for k, v := range m {
+		newM[k] = v
 	}
-	fieldCache.m[t] = f
-	fieldCache.Unlock()
+	newM[t] = f
+	fieldCache.value.Store(newM)
+	fieldCache.mu.Unlock()
 	return f
 }
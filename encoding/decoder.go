@@ -21,7 +21,42 @@ func Merge(dst interface{}, src interface{}) (err error) {
 	return decode(dst, src, false)
 }
 
+// Decoder decodes values in the same way as Decode but keeps the decoding
+// plan for each destination type, so repeatedly decoding into the same type
+// does not need to look up the shared decoder cache. A Decoder is not safe for
+// concurrent use.
+type Decoder struct {
+	decoders map[decoderCacheKey]decoderFunc
+}
+
+// NewDecoder returns a new Decoder.
+func NewDecoder() *Decoder {
+	return &Decoder{
+		decoders: make(map[decoderCacheKey]decoderFunc),
+	}
+}
+
+// Decode decodes src into dst, the first parameter must be a pointer.
+func (d *Decoder) Decode(dst interface{}, src interface{}) (err error) {
+	return decodeWith(dst, src, true, d.typeDecoder)
+}
+
+func (d *Decoder) typeDecoder(dt, st reflect.Type, blank bool) decoderFunc {
+	key := decoderCacheKey{dt, st, blank}
+	if f, ok := d.decoders[key]; ok {
+		return f
+	}
+
+	f := typeDecoder(dt, st, blank)
+	d.decoders[key] = f
+	return f
+}
+
 func decode(dst interface{}, src interface{}, blank bool) (err error) {
+	return decodeWith(dst, src, blank, typeDecoder)
+}
+
+func decodeWith(dst interface{}, src interface{}, blank bool, lookup func(dt, st reflect.Type, blank bool) decoderFunc) (err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			if _, ok := r.(runtime.Error); ok {
@@ -54,13 +89,13 @@ func decode(dst interface{}, src interface{}, blank bool) (err error) {
 		}
 	}
 
-	decodeValue(dv, sv, blank)
+	valueDecoder(dv, sv, blank, lookup)(dv, sv)
 	return nil
 }
 
 // decodeValue decodes the source value into the destination value
 func decodeValue(dv, sv reflect.Value, blank bool) {
-	valueDecoder(dv, sv, blank)(dv, sv)
+	valueDecoder(dv, sv, blank, typeDecoder)(dv, sv)
 }
 
 type decoderCacheKey struct {
@@ -73,7 +108,7 @@ var decoderCache struct {
 	m map[decoderCacheKey]decoderFunc
 }
 
-func valueDecoder(dv, sv reflect.Value, blank bool) decoderFunc {
+func valueDecoder(dv, sv reflect.Value, blank bool, lookup func(dt, st reflect.Type, blank bool) decoderFunc) decoderFunc {
 	if !sv.IsValid() {
 		return invalidValueDecoder
 	}
@@ -85,7 +120,7 @@ func valueDecoder(dv, sv reflect.Value, blank bool) decoderFunc {
 		}
 	}
 
-	return typeDecoder(dv.Type(), sv.Type(), blank)
+	return lookup(dv.Type(), sv.Type(), blank)
 }
 
 func typeDecoder(dt, st reflect.Type, blank bool) decoderFunc {
@@ -4,8 +4,13 @@ import (
 	"bytes"
 	"encoding/json"
 	"image"
+	"math"
+	"math/big"
+	"net"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 type T struct {
@@ -167,7 +172,7 @@ var decodeTests = []decodeTest{
 	{in: string("2"), ptr: new(interface{}), out: string("2")},
 	{in: "a\u1234", ptr: new(string), out: "a\u1234"},
 	{in: []interface{}{}, ptr: new([]string), out: []string{}},
-	{in: map[string]interface{}{"X": []interface{}{1, 2, 3}, "Y": 4}, ptr: new(T), out: T{}, err: &DecodeTypeError{reflect.TypeOf(""), reflect.TypeOf([]interface{}{}), ""}},
+	{in: map[string]interface{}{"X": []interface{}{1, 2, 3}, "Y": 4}, ptr: new(T), out: T{}, err: &DecodeTypeError{reflect.TypeOf(""), reflect.TypeOf([]interface{}{}), "", "X"}},
 	{in: map[string]interface{}{"x": 1}, ptr: new(tx), out: tx{}},
 	{in: map[string]interface{}{"F1": float64(1), "F2": 2, "F3": 3}, ptr: new(V), out: V{F1: float64(1), F2: int32(2), F3: string("3")}},
 	{in: map[string]interface{}{"F1": string("1"), "F2": 2, "F3": 3}, ptr: new(V), out: V{F1: string("1"), F2: int32(2), F3: string("3")}},
@@ -436,6 +441,28 @@ func TestDecodeUnmarshalerPointer(t *testing.T) {
 	}
 }
 
+func TestDecodeInline(t *testing.T) {
+	input := map[string]interface{}{
+		"id":     "1",
+		"city":   "London",
+		"tagged": map[string]interface{}{"city": "Paris"},
+	}
+	want := InlineUser{
+		ID:      "1",
+		Address: InlineAddress{City: "London"},
+		Tagged:  InlineAddress{City: "Paris"},
+	}
+
+	var got InlineUser
+	err := Decode(&got, input)
+	if err != nil {
+		t.Fatal("Decode:", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Decode: got %v want %v", got, want)
+	}
+}
+
 func TestDecodeMapIntKeys(t *testing.T) {
 	input := map[string]int{"1": 1, "2": 2, "3": 3}
 	want := map[int]int{1: 1, 2: 2, 3: 3}
@@ -450,6 +477,35 @@ func TestDecodeMapIntKeys(t *testing.T) {
 	}
 }
 
+func TestDecodeMapNonStringKeys(t *testing.T) {
+	outBool := map[bool]int{}
+	err := Decode(&outBool, map[string]interface{}{"true": 1, "false": 0})
+	if err != nil {
+		t.Errorf("got error %v, expected nil", err)
+	}
+	if want := map[bool]int{true: 1, false: 0}; !reflect.DeepEqual(outBool, want) {
+		t.Errorf("got %v, want %v", outBool, want)
+	}
+
+	outUint := map[uint8]int{}
+	err = Decode(&outUint, map[string]interface{}{"1": 1, "2": 2})
+	if err != nil {
+		t.Errorf("got error %v, expected nil", err)
+	}
+	if want := map[uint8]int{1: 1, 2: 2}; !reflect.DeepEqual(outUint, want) {
+		t.Errorf("got %v, want %v", outUint, want)
+	}
+
+	outText := map[TextKey]int{}
+	err = Decode(&outText, map[string]interface{}{"1,2": 1, "3,4": 2})
+	if err != nil {
+		t.Errorf("got error %v, expected nil", err)
+	}
+	if want := map[TextKey]int{{1, 2}: 1, {3, 4}: 2}; !reflect.DeepEqual(outText, want) {
+		t.Errorf("got %v, want %v", outText, want)
+	}
+}
+
 func TestDecodeCompoundKey(t *testing.T) {
 	input := map[string]interface{}{"id": []string{"1", "2"}, "err_a[]": "3", "err_b[": "4", "err_c]": "5"}
 	want := Compound{"1", "2", "3", "4", "5"}
@@ -478,6 +534,317 @@ func TestDecodeNilSlice(t *testing.T) {
 	}
 }
 
+func TestDecodeRawMessage(t *testing.T) {
+	input := map[string]interface{}{
+		"id":   "1",
+		"data": map[string]interface{}{"a": []interface{}{float64(1), "b", nil}},
+	}
+
+	out := RawMessageStruct{}
+	err := Decode(&out, input)
+	if err != nil {
+		t.Errorf("got error %v, expected nil", err)
+	}
+	if out.ID != "1" || string(out.Data) != `{"a":[1,"b",null]}` {
+		t.Errorf("got %s, want %s", out.Data, `{"a":[1,"b",null]}`)
+	}
+}
+
+func TestDecodeTextUnmarshaler(t *testing.T) {
+	input := map[string]interface{}{"ip": "127.0.0.1", "nil_ip": "::1", "id": "user-1"}
+	want := TextStruct{IP: net.ParseIP("127.0.0.1"), ID: TextID{"user", 1}}
+
+	out := TextStruct{}
+	err := Decode(&out, input)
+	if err != nil {
+		t.Errorf("got error %v, expected nil", err)
+	}
+	if !out.IP.Equal(want.IP) || out.ID != want.ID {
+		t.Errorf("got %v, want %v", out, want)
+	}
+	if out.NilIP == nil || !out.NilIP.Equal(net.ParseIP("::1")) {
+		t.Errorf("got %v, want ::1", out.NilIP)
+	}
+
+	err = Decode(&out, map[string]interface{}{"ip": "not an ip"})
+	if err == nil {
+		t.Errorf("got nil error, expected error from UnmarshalText")
+	}
+}
+
+func TestDecodeBigNumbers(t *testing.T) {
+	input := map[string]interface{}{
+		"int":   "123456789012345678901234567890",
+		"pint":  float64(-5),
+		"rat":   "1/3",
+		"float": json.Number("1.5"),
+	}
+
+	out := BigNumbers{}
+	err := Decode(&out, input)
+	if err != nil {
+		t.Errorf("got error %v, expected nil", err)
+	}
+	if out.Int.String() != "123456789012345678901234567890" {
+		t.Errorf("got %v, want 123456789012345678901234567890", out.Int.String())
+	}
+	if out.PInt == nil || out.PInt.Int64() != -5 {
+		t.Errorf("got %v, want -5", out.PInt)
+	}
+	if out.Rat == nil || out.Rat.Cmp(big.NewRat(1, 3)) != 0 {
+		t.Errorf("got %v, want 1/3", out.Rat)
+	}
+	if out.Float == nil || out.Float.Cmp(big.NewFloat(1.5)) != 0 {
+		t.Errorf("got %v, want 1.5", out.Float)
+	}
+
+	err = Decode(&out, map[string]interface{}{"int": float64(1.5)})
+	if err == nil {
+		t.Errorf("got nil error, expected error decoding 1.5 into big.Int")
+	}
+}
+
+type PathCustomer struct {
+	CreatedAt int `gorethink:"created_at"`
+}
+
+type PathOrder struct {
+	Customer PathCustomer `gorethink:"customer"`
+}
+
+func TestDecodeErrorPath(t *testing.T) {
+	input := map[string]interface{}{
+		"orders": []interface{}{
+			map[string]interface{}{"customer": map[string]interface{}{"created_at": 1}},
+			map[string]interface{}{"customer": map[string]interface{}{"created_at": "yesterday"}},
+		},
+	}
+
+	var out struct {
+		Orders []PathOrder `gorethink:"orders"`
+	}
+	err := Decode(&out, input)
+	if e, ok := err.(*DecodeTypeError); !ok || e.Path != "orders[1].customer.created_at" {
+		t.Errorf("got error %v, expected DecodeTypeError with path orders[1].customer.created_at", err)
+	}
+
+	var m map[string][]int
+	err = Decode(&m, map[string]interface{}{"a": []interface{}{"x"}})
+	if e, ok := err.(*DecodeTypeError); !ok || e.Path != "a[0]" {
+		t.Errorf("got error %v, expected DecodeTypeError with path a[0]", err)
+	}
+	if err != nil && !strings.HasPrefix(err.Error(), "gorethink: a[0]: could not decode type string") {
+		t.Errorf("got error message %q", err.Error())
+	}
+}
+
+type Payment interface {
+	Amount() int
+}
+
+type CreditCard struct {
+	Type   string `gorethink:"type"`
+	Number string `gorethink:"number"`
+	Total  int    `gorethink:"total"`
+}
+
+func (c CreditCard) Amount() int { return c.Total }
+
+type BankTransfer struct {
+	Type  string `gorethink:"type"`
+	Total int    `gorethink:"total"`
+}
+
+func (b *BankTransfer) Amount() int { return b.Total }
+
+func init() {
+	RegisterType((*Payment)(nil), "type", "credit_card", CreditCard{})
+	RegisterType((*Payment)(nil), "type", "bank_transfer", &BankTransfer{})
+}
+
+func TestDecodeRegisteredType(t *testing.T) {
+	input := map[string]interface{}{
+		"payment": map[string]interface{}{"type": "credit_card", "number": "4111", "total": 5},
+		"history": []interface{}{
+			map[string]interface{}{"type": "bank_transfer", "total": 3},
+		},
+	}
+
+	var out struct {
+		Payment Payment   `gorethink:"payment"`
+		History []Payment `gorethink:"history"`
+	}
+	err := Decode(&out, input)
+	if err != nil {
+		t.Fatalf("got error %v, expected nil", err)
+	}
+	want := CreditCard{Type: "credit_card", Number: "4111", Total: 5}
+	if !reflect.DeepEqual(out.Payment, want) {
+		t.Errorf("got %#v, want %#v", out.Payment, want)
+	}
+	if len(out.History) != 1 || !reflect.DeepEqual(out.History[0], &BankTransfer{Type: "bank_transfer", Total: 3}) {
+		t.Errorf("got %#v, want []Payment{&BankTransfer{...}}", out.History)
+	}
+
+	err = Decode(&out, map[string]interface{}{"payment": map[string]interface{}{"type": "cash"}})
+	if e, ok := err.(*DecodeTypeError); !ok || e.Path != "payment" {
+		t.Errorf("got error %v, expected DecodeTypeError for unknown type", err)
+	}
+}
+
+type GeneratedUser struct {
+	ID   string
+	Age  int
+	Tags []string
+}
+
+func init() {
+	RegisterGeneratedCodec((*GeneratedUser)(nil), func(p interface{}) (interface{}, error) {
+		v := p.(*GeneratedUser)
+		return map[string]interface{}{"id": v.ID, "age": v.Age, "generated": true}, nil
+	}, func(p interface{}, src map[string]interface{}) error {
+		v := p.(*GeneratedUser)
+		for k, val := range src {
+			switch k {
+			case "id":
+				x, err := DecodeString(val)
+				if err != nil {
+					return err
+				}
+				v.ID = x
+			case "age":
+				x, err := DecodeInt(val, 0)
+				if err != nil {
+					return err
+				}
+				v.Age = int(x)
+			case "tags":
+				if err := Decode(&v.Tags, val); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+func TestGeneratedCodec(t *testing.T) {
+	ev, err := Encode(GeneratedUser{ID: "a", Age: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{"id": "a", "age": 3, "generated": true}
+	if !reflect.DeepEqual(ev, want) {
+		t.Errorf("got %#v, want %#v", ev, want)
+	}
+
+	var out []GeneratedUser
+	err = Decode(&out, []interface{}{
+		map[string]interface{}{"id": "a", "age": json.Number("3"), "tags": []interface{}{"x"}},
+		map[string]interface{}{"id": "b", "age": float64(4)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantOut := []GeneratedUser{{ID: "a", Age: 3, Tags: []string{"x"}}, {ID: "b", Age: 4}}
+	if !reflect.DeepEqual(out, wantOut) {
+		t.Errorf("got %#v, want %#v", out, wantOut)
+	}
+
+	err = Decode(&out, []interface{}{map[string]interface{}{"age": "old"}})
+	if e, ok := err.(*DecodeTypeError); !ok || e.Path != "[0]" {
+		t.Errorf("got error %v, expected DecodeTypeError with path [0]", err)
+	}
+}
+
+func TestDecodeIntHelpers(t *testing.T) {
+	tests := []struct {
+		src   interface{}
+		bits  int
+		want  int64
+		valid bool
+	}{
+		{float64(-128), 8, -128, true},
+		{float64(127), 8, 127, true},
+		{float64(128), 8, 0, false},
+		{float64(1.5), 64, 0, false},
+		{float64(1e19), 64, 0, false},
+		{json.Number("-32768"), 16, -32768, true},
+		{json.Number("32768"), 16, 0, false},
+		{json.Number("1.0"), 64, 0, false},
+		{"40000", 16, 0, false},
+		{"40000", 32, 40000, true},
+	}
+	for _, tt := range tests {
+		got, err := DecodeInt(tt.src, tt.bits)
+		if !tt.valid {
+			if _, ok := err.(*DecodeTypeError); !ok {
+				t.Errorf("DecodeInt(%#v, %d): got error %v, expected DecodeTypeError", tt.src, tt.bits, err)
+			}
+		} else if err != nil || got != tt.want {
+			t.Errorf("DecodeInt(%#v, %d) = %d, %v, want %d", tt.src, tt.bits, got, err, tt.want)
+		}
+	}
+
+	utests := []struct {
+		src   interface{}
+		bits  int
+		want  uint64
+		valid bool
+	}{
+		{float64(255), 8, 255, true},
+		{float64(256), 8, 0, false},
+		{float64(-1), 64, 0, false},
+		{float64(0.5), 32, 0, false},
+		{json.Number("18446744073709551615"), 64, 18446744073709551615, true},
+		{json.Number("65536"), 16, 0, false},
+	}
+	for _, tt := range utests {
+		got, err := DecodeUint(tt.src, tt.bits)
+		if !tt.valid {
+			if _, ok := err.(*DecodeTypeError); !ok {
+				t.Errorf("DecodeUint(%#v, %d): got error %v, expected DecodeTypeError", tt.src, tt.bits, err)
+			}
+		} else if err != nil || got != tt.want {
+			t.Errorf("DecodeUint(%#v, %d) = %d, %v, want %d", tt.src, tt.bits, got, err, tt.want)
+		}
+	}
+
+	// Generated decoders do not truncate numbers
+	var out GeneratedUser
+	err := Decode(&out, map[string]interface{}{"age": float64(3.5)})
+	if _, ok := err.(*DecodeTypeError); !ok {
+		t.Errorf("got error %v, expected DecodeTypeError", err)
+	}
+}
+
+func TestDecoderReuse(t *testing.T) {
+	d := NewDecoder()
+	for i, input := range []map[string]interface{}{
+		{"X": "a", "Y": 1},
+		{"X": "b", "Y": 2},
+	} {
+		var out T
+		err := d.Decode(&out, input)
+		if err != nil {
+			t.Errorf("#%d: got error %v, expected nil", i, err)
+		}
+		want := T{X: input["X"].(string), Y: input["Y"].(int)}
+		if !jsonEqual(out, want) {
+			t.Errorf("#%d: got %q, want %q", i, out, want)
+		}
+	}
+	if len(d.decoders) != 1 {
+		t.Errorf("got %d cached decoders, want 1", len(d.decoders))
+	}
+
+	var out int
+	err := d.Decode(out, 1)
+	if err == nil {
+		t.Error("got nil error, expected error for non-pointer destination")
+	}
+}
+
 func jsonEqual(a, b interface{}) bool {
 	// First check using reflect.DeepEqual
 	if reflect.DeepEqual(a, b) {
@@ -525,3 +892,64 @@ func TestMergeMap(t *testing.T) {
 		t.Error("Field has been wiped")
 	}
 }
+
+// storedTime converts a TIME pseudo-type to the time read back from the
+// database, which only stores times with millisecond precision.
+func storedTime(pt interface{}) time.Time {
+	obj := pt.(map[string]interface{})
+	ms := int64(math.Floor(obj["epoch_time"].(float64)*1000 + 0.5))
+	loc, _ := time.Parse("-07:00", obj["timezone"].(string))
+
+	return time.Unix(0, 0).Add(time.Duration(ms) * time.Millisecond).In(loc.Location())
+}
+
+func TestDecodeTimeNanosRoundTrip(t *testing.T) {
+	times := []time.Time{
+		time.Date(2017, 5, 1, 12, 30, 15, 123456789, time.UTC),
+		time.Date(2017, 5, 1, 12, 30, 15, 999999999, time.FixedZone("", 5*3600+30*60)),
+		time.Date(2017, 5, 1, 12, 30, 15, 1, time.FixedZone("", -7*3600)),
+		time.Date(1960, 1, 1, 0, 0, 0, 999500001, time.FixedZone("", 3600)),
+	}
+
+	for _, want := range times {
+		in, err := Encode(TimeNanos{Created: want, Updated: &want})
+		if err != nil {
+			t.Fatalf("got error %v, expected nil", err)
+		}
+		m := in.(map[string]interface{})
+		m["created"] = storedTime(m["created"])
+		m["updated"] = storedTime(m["updated"])
+
+		var out TimeNanos
+		if err := Decode(&out, m); err != nil {
+			t.Fatalf("got error %v, expected nil", err)
+		}
+		_, wantOffset := want.Zone()
+		for _, got := range []time.Time{out.Created, *out.Updated} {
+			if !got.Equal(want) {
+				t.Errorf("got %v, want %v", got, want)
+			}
+			if _, offset := got.Zone(); offset != wantOffset {
+				t.Errorf("got offset %d, want %d", offset, wantOffset)
+			}
+		}
+	}
+}
+
+func TestDecodeTimeNanosMissing(t *testing.T) {
+	// Documents written without the nanos option keep the stored precision
+	want := time.Date(2017, 5, 1, 12, 30, 15, 123000000, time.UTC)
+
+	var out TimeNanos
+	if err := Decode(&out, map[string]interface{}{"created": want}); err != nil {
+		t.Fatalf("got error %v, expected nil", err)
+	}
+	if !out.Created.Equal(want) {
+		t.Errorf("got %v, want %v", out.Created, want)
+	}
+
+	err := Decode(&out, map[string]interface{}{"created": want, "created_nanos": float64(time.Second)})
+	if err == nil {
+		t.Errorf("got nil error, expected an out of range error")
+	}
+}
@@ -2,9 +2,12 @@ package encoding
 
 import (
 	"bytes"
+	"encoding"
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"strconv"
+	"time"
 )
 
 // newTypeDecoder constructs an decoderFunc for a type.
@@ -14,6 +17,31 @@ func newTypeDecoder(dt, st reflect.Type, blank bool) decoderFunc {
 		return unmarshalerDecoder
 	}
 
+	if dt == rawMessageType {
+		return rawMessageDecoder
+	}
+
+	if c, ok := lookupGeneratedCodec(dt); ok && c.dec != nil && blank && st == mapStringInterfaceType {
+		return newGeneratedDecoder(c.dec)
+	}
+
+	// Fallback to encoding.TextUnmarshaler for types which do not implement
+	// Unmarshaler when decoding strings, numbers are also accepted by structs
+	// such as big.Int which are stored as strings to avoid losing precision
+	if dt.Kind() != reflect.Ptr && !embedsInterface(dt, textUnmarshalerType) &&
+		reflect.PtrTo(dt).Implements(textUnmarshalerType) {
+		switch st.Kind() {
+		case reflect.String:
+			return textUnmarshalerDecoder
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+			reflect.Float32, reflect.Float64:
+			if dt.Kind() == reflect.Struct {
+				return numberAsTextUnmarshalerDecoder
+			}
+		}
+	}
+
 	if st.Kind() == reflect.Interface {
 		return newInterfaceAsTypeDecoder(blank)
 	}
@@ -95,11 +123,19 @@ func newTypeDecoder(dt, st reflect.Type, blank bool) decoderFunc {
 			return decodeTypeError
 		}
 	case reflect.Interface:
+		fallback := interfaceDecoder
 		if !st.AssignableTo(dt) {
-			return decodeTypeError
+			fallback = decodeTypeError
+		}
+
+		// Maps may be decoded into a type registered using RegisterType
+		if dt.NumMethod() > 0 && st.Kind() == reflect.Map {
+			if kind := st.Key().Kind(); kind == reflect.String || kind == reflect.Interface {
+				return newRegisteredInterfaceDecoder(dt, st, blank, fallback)
+			}
 		}
 
-		return interfaceDecoder
+		return fallback
 	case reflect.Ptr:
 		return newPtrDecoder(dt, st, blank)
 	case reflect.Map:
@@ -225,10 +261,54 @@ func unmarshalerDecoder(dv, sv reflect.Value) {
 	u := dv.Interface().(Unmarshaler)
 	err := u.UnmarshalRQL(sv.Interface())
 	if err != nil {
-		panic(&DecodeTypeError{dv.Type(), sv.Type(), err.Error()})
+		panic(&DecodeTypeError{DestType: dv.Type(), SrcType: sv.Type(), Reason: err.Error()})
+	}
+}
+
+func textUnmarshalerDecoder(dv, sv reflect.Value) {
+	unmarshalText(dv, sv, []byte(sv.String()))
+}
+
+func numberAsTextUnmarshalerDecoder(dv, sv reflect.Value) {
+	var b []byte
+	switch sv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		b = strconv.AppendInt(b, sv.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		b = strconv.AppendUint(b, sv.Uint(), 10)
+	default:
+		b = strconv.AppendFloat(b, sv.Float(), 'f', -1, sv.Type().Bits())
+	}
+
+	unmarshalText(dv, sv, b)
+}
+
+func unmarshalText(dv, sv reflect.Value, b []byte) {
+	if dv.Kind() != reflect.Ptr && dv.Type().Name() != "" && dv.CanAddr() {
+		dv = dv.Addr()
+	}
+
+	if dv.IsNil() {
+		dv.Set(reflect.New(dv.Type().Elem()))
+	}
+
+	u := dv.Interface().(encoding.TextUnmarshaler)
+	err := u.UnmarshalText(b)
+	if err != nil {
+		panic(&DecodeTypeError{DestType: dv.Type(), SrcType: sv.Type(), Reason: err.Error()})
 	}
 }
 
+// rawMessageDecoder stores the JSON encoding of the source value
+func rawMessageDecoder(dv, sv reflect.Value) {
+	b, err := json.Marshal(sv.Interface())
+	if err != nil {
+		panic(&DecodeTypeError{DestType: dv.Type(), SrcType: sv.Type(), Reason: err.Error()})
+	}
+
+	dv.SetBytes(b)
+}
+
 // Boolean decoders
 
 func boolAsBoolDecoder(dv, sv reflect.Value) {
@@ -326,7 +406,7 @@ func stringAsBoolDecoder(dv, sv reflect.Value) {
 	} else if sv.String() == "" {
 		dv.SetBool(false)
 	} else {
-		panic(&DecodeTypeError{dv.Type(), sv.Type(), err.Error()})
+		panic(&DecodeTypeError{DestType: dv.Type(), SrcType: sv.Type(), Reason: err.Error()})
 	}
 }
 func stringAsIntDecoder(dv, sv reflect.Value) {
@@ -334,7 +414,7 @@ func stringAsIntDecoder(dv, sv reflect.Value) {
 	if err == nil {
 		dv.SetInt(i)
 	} else {
-		panic(&DecodeTypeError{dv.Type(), sv.Type(), err.Error()})
+		panic(&DecodeTypeError{DestType: dv.Type(), SrcType: sv.Type(), Reason: err.Error()})
 	}
 }
 func stringAsUintDecoder(dv, sv reflect.Value) {
@@ -342,7 +422,7 @@ func stringAsUintDecoder(dv, sv reflect.Value) {
 	if err == nil {
 		dv.SetUint(i)
 	} else {
-		panic(&DecodeTypeError{dv.Type(), sv.Type(), err.Error()})
+		panic(&DecodeTypeError{DestType: dv.Type(), SrcType: sv.Type(), Reason: err.Error()})
 	}
 }
 func stringAsFloatDecoder(dv, sv reflect.Value) {
@@ -350,7 +430,7 @@ func stringAsFloatDecoder(dv, sv reflect.Value) {
 	if err == nil {
 		dv.SetFloat(f)
 	} else {
-		panic(&DecodeTypeError{dv.Type(), sv.Type(), err.Error()})
+		panic(&DecodeTypeError{DestType: dv.Type(), SrcType: sv.Type(), Reason: err.Error()})
 	}
 }
 func stringAsStringDecoder(dv, sv reflect.Value) {
@@ -386,6 +466,11 @@ func (d *arrayDecoder) decode(dv, sv reflect.Value) {
 	// Iterate through the slice/array and decode each element before adding it
 	// to the dest slice/array
 	i := 0
+	defer func() {
+		if r := recover(); r != nil {
+			panic(prependErrorPath(r, "["+strconv.Itoa(i)+"]"))
+		}
+	}()
 	for i < sv.Len() {
 		if dv.Kind() == reflect.Slice {
 			// Get element of array, growing if necessary.
@@ -449,7 +534,16 @@ func (d *mapAsMapDecoder) decode(dv, sv reflect.Value) {
 	keyType := dv.Type().Key()
 	elemType := dv.Type().Elem()
 
+	var path string
+	defer func() {
+		if r := recover(); r != nil {
+			panic(prependErrorPath(r, path))
+		}
+	}()
+
 	for _, sElemKey := range sv.MapKeys() {
+		path = fmt.Sprint(sElemKey.Interface())
+
 		var dElemKey reflect.Value
 		var dElemVal reflect.Value
 
@@ -486,9 +580,18 @@ type mapAsStructDecoder struct {
 }
 
 func (d *mapAsStructDecoder) decode(dv, sv reflect.Value) {
+	var path string
+	defer func() {
+		if r := recover(); r != nil {
+			panic(prependErrorPath(r, path))
+		}
+	}()
+
 	for _, kv := range sv.MapKeys() {
+		path = kv.String()
+
 		var f *field
-		var compoundFields = []*field{}
+		var compoundFields []*field
 		var fieldDec decoderFunc
 		key := []byte(kv.String())
 		for i := range d.fields {
@@ -520,6 +623,7 @@ func (d *mapAsStructDecoder) decode(dv, sv reflect.Value) {
 					sElemVal = sElemVal.Elem()
 				}
 				sElemVal = sElemVal.Index(compoundField.compoundIndex)
+				path = kv.String() + "[" + strconv.Itoa(compoundField.compoundIndex) + "]"
 				fieldDec = typeDecoder(dElemVal.Type(), sElemVal.Type(), d.blank)
 
 				if !sElemVal.IsValid() || !dElemVal.CanSet() {
@@ -539,6 +643,58 @@ func (d *mapAsStructDecoder) decode(dv, sv reflect.Value) {
 			fieldDec(dElemVal, sElemVal)
 		}
 	}
+
+	for i := range d.fields {
+		if d.fields[i].nanos {
+			path = d.fields[i].name + nanosFieldSuffix
+			decodeTimeNanos(dv, sv, &d.fields[i])
+		}
+	}
+}
+
+// decodeTimeNanos restores the nanoseconds of a time field with the nanos
+// option from the field stored alongside it, if the field exists.
+func decodeTimeNanos(dv, sv reflect.Value, f *field) {
+	keyType := sv.Type().Key()
+	if keyType.Kind() != reflect.String && keyType.Kind() != reflect.Interface {
+		return
+	}
+	if !sv.MapIndex(reflect.ValueOf(f.name).Convert(keyType)).IsValid() {
+		return
+	}
+	nv := sv.MapIndex(reflect.ValueOf(f.name + nanosFieldSuffix).Convert(keyType))
+	if !nv.IsValid() {
+		return
+	}
+
+	nanos, err := DecodeInt64(nv.Interface())
+	if err != nil {
+		panic(err)
+	}
+	if nanos < 0 || nanos >= int64(time.Second) {
+		panic(&DecodeTypeError{
+			DestType: timeType,
+			SrcType:  reflect.TypeOf(nv.Interface()),
+			Reason:   fmt.Sprintf("nanoseconds %d out of range", nanos),
+		})
+	}
+
+	tv := fieldByIndexNoAlloc(dv, f.index)
+	if tv.Kind() == reflect.Ptr {
+		if tv.IsNil() {
+			return
+		}
+		tv = tv.Elem()
+	}
+	if !tv.IsValid() || !tv.CanSet() {
+		return
+	}
+
+	// The stored time is rounded to milliseconds so round to the nearest
+	// second before adding the nanoseconds
+	t := tv.Interface().(time.Time)
+	sec := t.Add(-time.Duration(nanos)).Add(time.Second / 2).Unix()
+	tv.Set(reflect.ValueOf(time.Unix(sec, nanos).In(t.Location())))
 }
 
 func newMapAsStructDecoder(dt, st reflect.Type, blank bool) decoderFunc {
@@ -30,7 +30,17 @@ func Encode(v interface{}) (ev interface{}, err error) {
 		}
 	}()
 
-	return encode(reflect.ValueOf(v)), nil
+	rv := reflect.ValueOf(v)
+
+	// Copy values into an addressable value so that methods with pointer
+	// receivers (such as the MarshalText method of big.Int) can be used
+	if rv.IsValid() && rv.Kind() != reflect.Ptr {
+		av := reflect.New(rv.Type()).Elem()
+		av.Set(rv)
+		rv = av
+	}
+
+	return encode(rv), nil
 }
 
 func encode(v reflect.Value) interface{} {
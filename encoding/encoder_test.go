@@ -1,8 +1,15 @@
 package encoding
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"image"
+	"math/big"
+	"net"
 	"reflect"
+	"strconv"
 	"testing"
 	"time"
 )
@@ -109,6 +116,21 @@ func TestOmitEmpty(t *testing.T) {
 	}
 }
 
+func TestOmitEmptyZeroTimeWithLocation(t *testing.T) {
+	var o Optionals
+	o.Tr = time.Unix(0, 0).In(time.UTC)
+	o.To = time.Time{}.In(time.FixedZone("+01:00", 3600))
+	o.Mr = map[string]interface{}{}
+
+	got, err := Encode(&o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !jsonEqual(got, optionalsExpected) {
+		t.Errorf("\ngot:  %#v\nwant: %#v\n", got, optionalsExpected)
+	}
+}
+
 type IntType int
 
 type MyStruct struct {
@@ -261,6 +283,56 @@ func TestDuplicatedFieldDisappears(t *testing.T) {
 	}
 }
 
+type InlineAddress struct {
+	City    string `gorethink:"city"`
+	Country string `gorethink:"country,omitempty"`
+}
+
+type InlineUser struct {
+	ID      string        `gorethink:"id"`
+	Address InlineAddress `gorethink:",inline"`
+	Tagged  InlineAddress `gorethink:"tagged"`
+}
+
+func TestEncodeInline(t *testing.T) {
+	v := InlineUser{
+		ID:      "1",
+		Address: InlineAddress{City: "London"},
+		Tagged:  InlineAddress{City: "Paris", Country: "France"},
+	}
+	got, err := Encode(v)
+	if err != nil {
+		t.Fatal("Encode:", err)
+	}
+	want := map[string]interface{}{
+		"id":   "1",
+		"city": "London",
+		"tagged": map[string]interface{}{
+			"city":    "Paris",
+			"country": "France",
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Encode: got %v want %v", got, want)
+	}
+}
+
+type InlinePointer struct {
+	ID      string         `gorethink:"id"`
+	Address *InlineAddress `gorethink:",inline"`
+}
+
+func TestEncodeInlineNilPointer(t *testing.T) {
+	got, err := Encode(InlinePointer{ID: "1"})
+	if err != nil {
+		t.Fatal("Encode:", err)
+	}
+	want := map[string]interface{}{"id": "1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Encode: got %v want %v", got, want)
+	}
+}
+
 func TestEncodeMapIntKeys(t *testing.T) {
 	input := map[int]int{1: 1, 2: 2, 3: 3}
 	want := map[string]int{"1": 1, "2": 2, "3": 3}
@@ -274,6 +346,78 @@ func TestEncodeMapIntKeys(t *testing.T) {
 	}
 }
 
+type BigNumbers struct {
+	Int    big.Int    `gorethink:"int"`
+	PInt   *big.Int   `gorethink:"pint"`
+	Rat    *big.Rat   `gorethink:"rat"`
+	Float  *big.Float `gorethink:"float"`
+	NilInt *big.Int   `gorethink:"nil_int"`
+}
+
+func TestEncodeBigNumbers(t *testing.T) {
+	i, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	input := BigNumbers{
+		Int:   *i,
+		PInt:  big.NewInt(-5),
+		Rat:   big.NewRat(1, 3),
+		Float: big.NewFloat(1.5),
+	}
+	want := map[string]interface{}{
+		"int":     "123456789012345678901234567890",
+		"pint":    "-5",
+		"rat":     "1/3",
+		"float":   "1.5",
+		"nil_int": nil,
+	}
+
+	// Values are encoded without having to pass a pointer
+	out, err := Encode(input)
+	if err != nil {
+		t.Errorf("got error %v, expected nil", err)
+	}
+	if !reflect.DeepEqual(out, want) {
+		t.Errorf("got %v, want %v", out, want)
+	}
+}
+
+type TextKey struct {
+	X, Y int
+}
+
+func (k TextKey) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("%d,%d", k.X, k.Y)), nil
+}
+
+func (k *TextKey) UnmarshalText(b []byte) error {
+	_, err := fmt.Sscanf(string(b), "%d,%d", &k.X, &k.Y)
+	return err
+}
+
+func TestEncodeMapNonStringKeys(t *testing.T) {
+	inputs := []interface{}{
+		map[bool]int{true: 1, false: 0},
+		map[uint8]int{1: 1, 2: 2},
+		map[float64]int{1.5: 1},
+		map[TextKey]int{{1, 2}: 1, {3, 4}: 2},
+	}
+	wants := []interface{}{
+		map[string]int{"true": 1, "false": 0},
+		map[string]int{"1": 1, "2": 2},
+		map[string]int{"1.5": 1},
+		map[string]int{"1,2": 1, "3,4": 2},
+	}
+
+	for i, input := range inputs {
+		out, err := Encode(input)
+		if err != nil {
+			t.Errorf("got error %v, expected nil", err)
+		}
+		if !jsonEqual(out, wants[i]) {
+			t.Errorf("got %q, want %q", out, wants[i])
+		}
+	}
+}
+
 type RefA struct {
 	ID string `gorethink:"id,omitempty"`
 	B  *RefB  `gorethink:"b_id,reference" gorethink_ref:"id"`
@@ -348,6 +492,109 @@ func TestReferenceFieldArray(t *testing.T) {
 	}
 }
 
+type RawMessageStruct struct {
+	ID   string          `gorethink:"id"`
+	Data json.RawMessage `gorethink:"data"`
+}
+
+func TestEncodeRawMessage(t *testing.T) {
+	input := RawMessageStruct{"1", json.RawMessage(`{"a":[1,"b",null]}`)}
+	want := map[string]interface{}{
+		"id": "1",
+		"data": map[string]interface{}{
+			"a": []interface{}{float64(1), "b", nil},
+		},
+	}
+
+	out, err := Encode(input)
+	if err != nil {
+		t.Errorf("got error %v, expected nil", err)
+	}
+	if !reflect.DeepEqual(out, want) {
+		t.Errorf("got %v, want %v", out, want)
+	}
+
+	out, err = Encode(RawMessageStruct{ID: "1"})
+	if err != nil {
+		t.Errorf("got error %v, expected nil", err)
+	}
+	if !reflect.DeepEqual(out, map[string]interface{}{"id": "1", "data": nil}) {
+		t.Errorf("got %v, want nil data", out)
+	}
+
+	_, err = Encode(RawMessageStruct{"1", json.RawMessage(`{`)})
+	if err == nil {
+		t.Errorf("got nil error, expected error for invalid JSON")
+	}
+}
+
+type TextID struct {
+	Prefix string
+	Num    int
+}
+
+func (id *TextID) MarshalText() ([]byte, error) {
+	if id.Prefix == "" {
+		return nil, errors.New("missing prefix")
+	}
+	return []byte(fmt.Sprintf("%s-%d", id.Prefix, id.Num)), nil
+}
+
+func (id *TextID) UnmarshalText(b []byte) error {
+	i := bytes.LastIndexByte(b, '-')
+	if i < 0 {
+		return errors.New("missing prefix")
+	}
+	num, err := strconv.Atoi(string(b[i+1:]))
+	if err != nil {
+		return err
+	}
+	id.Prefix, id.Num = string(b[:i]), num
+	return nil
+}
+
+type TextStruct struct {
+	IP    net.IP  `gorethink:"ip"`
+	NilIP *net.IP `gorethink:"nil_ip"`
+	ID    TextID  `gorethink:"id"`
+}
+
+type EmbeddedTime struct {
+	time.Time
+	Name string
+}
+
+func TestEncodeTextMarshaler(t *testing.T) {
+	input := TextStruct{IP: net.ParseIP("127.0.0.1"), ID: TextID{"user", 1}}
+	want := map[string]interface{}{
+		"ip":     "127.0.0.1",
+		"nil_ip": nil,
+		"id":     "user-1",
+	}
+
+	out, err := Encode(&input)
+	if err != nil {
+		t.Errorf("got error %v, expected nil", err)
+	}
+	if !reflect.DeepEqual(out, want) {
+		t.Errorf("got %v, want %v", out, want)
+	}
+
+	_, err = Encode(&TextStruct{})
+	if err == nil {
+		t.Errorf("got nil error, expected error from MarshalText")
+	}
+
+	// Promoted MarshalText methods should not replace the struct
+	out, err = Encode(EmbeddedTime{time.Unix(0, 0).In(time.UTC), "a"})
+	if err != nil {
+		t.Errorf("got error %v, expected nil", err)
+	}
+	if m, ok := out.(map[string]interface{}); !ok || m["Name"] != "a" {
+		t.Errorf("got %v, want struct encoded as map", out)
+	}
+}
+
 func TestEncodeBytes(t *testing.T) {
 	type BytesStruct struct {
 		A []byte
@@ -420,3 +667,29 @@ func TestEncodeNilSlice(t *testing.T) {
 		t.Errorf("got %q, want %q", out, want)
 	}
 }
+
+type TimeNanos struct {
+	Created time.Time  `gorethink:"created,nanos"`
+	Updated *time.Time `gorethink:"updated,nanos,omitempty"`
+}
+
+func TestEncodeTimeNanos(t *testing.T) {
+	created := time.Date(2017, 5, 1, 12, 30, 15, 123456789, time.FixedZone("", 5*3600+30*60))
+	out, err := Encode(TimeNanos{Created: created})
+	if err != nil {
+		t.Fatalf("got error %v, expected nil", err)
+	}
+
+	m := out.(map[string]interface{})
+	if got := m["created_nanos"]; got != int64(123456789) {
+		t.Errorf("got created_nanos %v, want 123456789", got)
+	}
+	if got := m["created"].(map[string]interface{})["timezone"]; got != "+05:30" {
+		t.Errorf("got timezone %v, want +05:30", got)
+	}
+	for _, key := range []string{"updated", "updated_nanos"} {
+		if _, ok := m[key]; ok {
+			t.Errorf("got %s, expected it to be omitted", key)
+		}
+	}
+}
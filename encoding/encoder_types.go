@@ -1,7 +1,9 @@
 package encoding
 
 import (
+	"encoding"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"math"
 	"reflect"
@@ -20,10 +22,27 @@ func newTypeEncoder(t reflect.Type, allowAddr bool) encoderFunc {
 		}
 	}
 
+	if c, ok := lookupGeneratedCodec(t); ok && c.enc != nil {
+		return newGeneratedEncoder(c.enc)
+	}
+
 	// Check for psuedo-types first
 	switch t {
 	case timeType:
 		return timePseudoTypeEncoder
+	case rawMessageType:
+		return rawMessageEncoder
+	}
+
+	// Fallback to encoding.TextMarshaler for types which do not implement
+	// Marshaler, pointers are handled by the pointer encoder
+	if t.Kind() != reflect.Ptr && !embedsInterface(t, textMarshalerType) {
+		if t.Implements(textMarshalerType) {
+			return textMarshalerEncoder
+		}
+		if allowAddr && reflect.PtrTo(t).Implements(textMarshalerType) {
+			return newCondAddrEncoder(addrTextMarshalerEncoder, newTypeEncoder(t, false))
+		}
 	}
 
 	switch t.Kind() {
@@ -95,6 +114,30 @@ func addrMarshalerEncoder(v reflect.Value) interface{} {
 	return ev
 }
 
+func textMarshalerEncoder(v reflect.Value) interface{} {
+	m := v.Interface().(encoding.TextMarshaler)
+	b, err := m.MarshalText()
+	if err != nil {
+		panic(&MarshalerError{v.Type(), err})
+	}
+
+	return string(b)
+}
+
+func addrTextMarshalerEncoder(v reflect.Value) interface{} {
+	va := v.Addr()
+	if va.IsNil() {
+		return nil
+	}
+	m := va.Interface().(encoding.TextMarshaler)
+	b, err := m.MarshalText()
+	if err != nil {
+		panic(&MarshalerError{v.Type(), err})
+	}
+
+	return string(b)
+}
+
 func boolEncoder(v reflect.Value) interface{} {
 	if v.Bool() {
 		return true
@@ -149,7 +192,7 @@ type structEncoder struct {
 func (se *structEncoder) encode(v reflect.Value) interface{} {
 	m := make(map[string]interface{})
 	for i, f := range se.fields {
-		fv := fieldByIndex(v, f.index)
+		fv := fieldByIndexNoAlloc(v, f.index)
 		if !fv.IsValid() || f.omitEmpty && se.isEmptyValue(fv) {
 			continue
 		}
@@ -177,6 +220,18 @@ func (se *structEncoder) encode(v reflect.Value) interface{} {
 		}
 
 		m[f.name] = encField
+
+		// Times with the nanos option also store the nanoseconds within the
+		// second as RethinkDB only stores times with millisecond precision
+		if f.nanos {
+			if fv.Kind() == reflect.Ptr {
+				if fv.IsNil() {
+					continue
+				}
+				fv = fv.Elem()
+			}
+			m[f.name+nanosFieldSuffix] = int64(fv.Interface().(time.Time).Nanosecond())
+		}
 	}
 
 	return m
@@ -218,7 +273,10 @@ func extractValue(encField interface{}, v reflect.Value, name string, refName st
 
 func (se *structEncoder) isEmptyValue(v reflect.Value) bool {
 	if v.Type() == timeType {
-		return v.Interface().(time.Time) == time.Time{}
+		return v.Interface().(time.Time).IsZero()
+	}
+	if v.Kind() != reflect.Ptr && v.Kind() != reflect.Interface && v.Type().Implements(emptierType) {
+		return v.Interface().(Emptier).IsEmptyRQL()
 	}
 
 	return isEmptyValue(v)
@@ -256,6 +314,14 @@ func (me *mapEncoder) encode(v reflect.Value) interface{} {
 
 func newMapEncoder(t reflect.Type) encoderFunc {
 	var keyEnc encoderFunc
+
+	// String keys are used directly, other keys which implement
+	// encoding.TextMarshaler are converted using MarshalText
+	if t.Key().Kind() != reflect.String && t.Key().Implements(textMarshalerType) {
+		me := &mapEncoder{textMarshalerEncoder, typeEncoder(t.Elem())}
+		return me.encode
+	}
+
 	switch t.Key().Kind() {
 	case reflect.Bool:
 		keyEnc = asStringEncoder
@@ -364,10 +430,10 @@ func timePseudoTypeEncoder(v reflect.Value) interface{} {
 
 	timeVal := float64(t.UnixNano()) / float64(time.Second)
 
-	// use seconds-since-epoch precision if time.Time `t`
-	// is before the oldest nanosecond time
+	// time.Time `t` is before the oldest nanosecond time so add the
+	// fractional seconds separately
 	if t.Before(time.Unix(0, math.MinInt64)) {
-		timeVal = float64(t.Unix())
+		timeVal = float64(t.Unix()) + float64(t.Nanosecond())/float64(time.Second)
 	}
 
 	return map[string]interface{}{
@@ -377,6 +443,21 @@ func timePseudoTypeEncoder(v reflect.Value) interface{} {
 	}
 }
 
+// Encode a json.RawMessage by unmarshaling the JSON it contains
+func rawMessageEncoder(v reflect.Value) interface{} {
+	b := v.Bytes()
+	if len(b) == 0 {
+		return nil
+	}
+
+	var ev interface{}
+	if err := json.Unmarshal(b, &ev); err != nil {
+		panic(&MarshalerError{v.Type(), err})
+	}
+
+	return ev
+}
+
 // Encode a byte slice to the BINARY RQL type
 func encodeByteSlice(v reflect.Value) interface{} {
 	var b []byte
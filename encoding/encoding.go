@@ -1,17 +1,24 @@
 package encoding
 
 import (
+	"encoding"
+	"encoding/json"
 	"reflect"
 	"time"
 )
 
 var (
 	// type constants
-	stringType = reflect.TypeOf("")
-	timeType   = reflect.TypeOf(new(time.Time)).Elem()
+	stringType     = reflect.TypeOf("")
+	timeType       = reflect.TypeOf(new(time.Time)).Elem()
+	rawMessageType = reflect.TypeOf(new(json.RawMessage)).Elem()
 
 	marshalerType   = reflect.TypeOf(new(Marshaler)).Elem()
 	unmarshalerType = reflect.TypeOf(new(Unmarshaler)).Elem()
+	emptierType     = reflect.TypeOf(new(Emptier)).Elem()
+
+	textMarshalerType   = reflect.TypeOf(new(encoding.TextMarshaler)).Elem()
+	textUnmarshalerType = reflect.TypeOf(new(encoding.TextUnmarshaler)).Elem()
 )
 
 // Marshaler is the interface implemented by objects that
@@ -26,7 +33,16 @@ type Unmarshaler interface {
 	UnmarshalRQL(interface{}) error
 }
 
+// Emptier is the interface implemented by types which decide whether they are
+// empty, struct fields tagged with omitempty are not encoded if IsEmptyRQL
+// returns true.
+type Emptier interface {
+	IsEmptyRQL() bool
+}
+
 func init() {
 	encoderCache.m = make(map[reflect.Type]encoderFunc)
 	decoderCache.m = make(map[decoderCacheKey]decoderFunc)
+	typeRegistry.m = make(map[reflect.Type]*interfaceTypes)
+	generatedCodecs.m = make(map[reflect.Type]generatedCodec)
 }
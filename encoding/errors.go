@@ -31,19 +31,39 @@ func (e *InvalidUnmarshalError) Error() string {
 }
 
 // An InvalidTypeError describes a value that was
-// not appropriate for a value of a specific Go type.
+// not appropriate for a value of a specific Go type. If the value was nested
+// inside the decoded document Path contains its location, for example
+// "orders[3].customer.created_at".
 type DecodeTypeError struct {
 	DestType, SrcType reflect.Type
 	Reason            string
+	Path              string
 }
 
 func (e *DecodeTypeError) Error() string {
+	prefix := "gorethink: "
+	if e.Path != "" {
+		prefix += e.Path + ": "
+	}
+
 	if e.Reason != "" {
-		return "gorethink: could not decode type " + e.SrcType.String() + " into Go value of type " + e.DestType.String() + ": " + e.Reason
+		return prefix + "could not decode type " + e.SrcType.String() + " into Go value of type " + e.DestType.String() + ": " + e.Reason
 	} else {
-		return "gorethink: could not decode type " + e.SrcType.String() + " into Go value of type " + e.DestType.String()
+		return prefix + "could not decode type " + e.SrcType.String() + " into Go value of type " + e.DestType.String()
+	}
+}
 
+// prependErrorPath adds elem to the front of the path of a DecodeTypeError
+// panic value, any other value is returned unchanged.
+func prependErrorPath(r interface{}, elem string) interface{} {
+	if e, ok := r.(*DecodeTypeError); ok {
+		if e.Path != "" && !strings.HasPrefix(e.Path, "[") {
+			elem += "."
+		}
+		e.Path = elem + e.Path
 	}
+
+	return r
 }
 
 // An UnsupportedTypeError is returned by Marshal when attempting
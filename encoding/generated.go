@@ -0,0 +1,232 @@
+package encoding
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"sync"
+)
+
+var mapStringInterfaceType = reflect.TypeOf(map[string]interface{}(nil))
+
+// GeneratedEncoder encodes the struct pointed to by v without using
+// reflection.
+type GeneratedEncoder func(v interface{}) (interface{}, error)
+
+// GeneratedDecoder decodes src into the struct pointed to by v without using
+// reflection.
+type GeneratedDecoder func(v interface{}, src map[string]interface{}) error
+
+type generatedCodec struct {
+	enc GeneratedEncoder
+	dec GeneratedDecoder
+}
+
+var generatedCodecs struct {
+	sync.RWMutex
+	m map[reflect.Type]generatedCodec
+}
+
+// RegisterGeneratedCodec registers functions used to encode and decode the
+// struct type pointed to by v instead of the reflection based encoder and
+// decoder. Either function may be nil. It is usually called from the init
+// functions written by the gorethink-gen tool and must be called before the
+// type is first encoded or decoded.
+//
+// The generated decoder is only used by Decode when decoding objects, values
+// decoded using Merge or from other types still use reflection.
+func RegisterGeneratedCodec(v interface{}, enc GeneratedEncoder, dec GeneratedDecoder) {
+	t := reflect.TypeOf(v)
+	if t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+		panic("gorethink: RegisterGeneratedCodec requires a pointer to a struct type")
+	}
+
+	generatedCodecs.Lock()
+	generatedCodecs.m[t.Elem()] = generatedCodec{enc, dec}
+	generatedCodecs.Unlock()
+}
+
+func lookupGeneratedCodec(t reflect.Type) (generatedCodec, bool) {
+	generatedCodecs.RLock()
+	c, ok := generatedCodecs.m[t]
+	generatedCodecs.RUnlock()
+	return c, ok
+}
+
+func newGeneratedEncoder(enc GeneratedEncoder) encoderFunc {
+	return func(v reflect.Value) interface{} {
+		var p reflect.Value
+		if v.CanAddr() {
+			p = v.Addr()
+		} else {
+			p = reflect.New(v.Type())
+			p.Elem().Set(v)
+		}
+
+		ev, err := enc(p.Interface())
+		if err != nil {
+			panic(&MarshalerError{v.Type(), err})
+		}
+		return ev
+	}
+}
+
+func newGeneratedDecoder(dec GeneratedDecoder) decoderFunc {
+	return func(dv, sv reflect.Value) {
+		if sv.IsNil() {
+			return
+		}
+
+		err := dec(dv.Addr().Interface(), sv.Interface().(map[string]interface{}))
+		if err != nil {
+			if e, ok := err.(*DecodeTypeError); ok {
+				panic(e)
+			}
+			panic(&DecodeTypeError{DestType: dv.Type(), SrcType: sv.Type(), Reason: err.Error()})
+		}
+	}
+}
+
+// DecodeString decodes src into a string, it is used by generated decoders.
+func DecodeString(src interface{}) (string, error) {
+	if s, ok := src.(string); ok {
+		return s, nil
+	}
+
+	var s string
+	err := Decode(&s, src)
+	return s, err
+}
+
+// DecodeBool decodes src into a bool, it is used by generated decoders.
+func DecodeBool(src interface{}) (bool, error) {
+	if b, ok := src.(bool); ok {
+		return b, nil
+	}
+
+	var b bool
+	err := Decode(&b, src)
+	return b, err
+}
+
+// DecodeInt64 decodes src into an int64, it is used by generated decoders.
+func DecodeInt64(src interface{}) (int64, error) {
+	return DecodeInt(src, 64)
+}
+
+// DecodeInt decodes src into a signed integer of the given size in bits, or
+// the size of int if bits is 0, it is used by generated decoders. Numbers
+// which are not integers or do not fit in the integer type are not
+// truncated, a DecodeTypeError is returned instead.
+func DecodeInt(src interface{}, bits int) (int64, error) {
+	dt := intTypes[bits]
+	if bits == 0 {
+		bits = strconv.IntSize
+	}
+
+	var n int64
+	switch x := src.(type) {
+	case float64:
+		max := math.Ldexp(1, bits-1)
+		if x != math.Trunc(x) || x < -max || x >= max {
+			return 0, numberTypeError(dt, src)
+		}
+		return int64(x), nil
+	case json.Number:
+		var err error
+		if n, err = strconv.ParseInt(string(x), 10, bits); err != nil {
+			return 0, &DecodeTypeError{DestType: dt, SrcType: reflect.TypeOf(src), Reason: err.Error()}
+		}
+		return n, nil
+	}
+
+	if err := Decode(&n, src); err != nil {
+		return 0, err
+	}
+	if bits < 64 && (n < -1<<uint(bits-1) || n >= 1<<uint(bits-1)) {
+		return 0, numberTypeError(dt, src)
+	}
+	return n, nil
+}
+
+// DecodeUint64 decodes src into a uint64, it is used by generated decoders.
+func DecodeUint64(src interface{}) (uint64, error) {
+	return DecodeUint(src, 64)
+}
+
+// DecodeUint decodes src into an unsigned integer of the given size in bits,
+// or the size of uint if bits is 0, it is used by generated decoders. Numbers
+// which are not integers or do not fit in the integer type are not
+// truncated, a DecodeTypeError is returned instead.
+func DecodeUint(src interface{}, bits int) (uint64, error) {
+	dt := uintTypes[bits]
+	if bits == 0 {
+		bits = strconv.IntSize
+	}
+
+	var n uint64
+	switch x := src.(type) {
+	case float64:
+		if x != math.Trunc(x) || x < 0 || x >= math.Ldexp(1, bits) {
+			return 0, numberTypeError(dt, src)
+		}
+		return uint64(x), nil
+	case json.Number:
+		var err error
+		if n, err = strconv.ParseUint(string(x), 10, bits); err != nil {
+			return 0, &DecodeTypeError{DestType: dt, SrcType: reflect.TypeOf(src), Reason: err.Error()}
+		}
+		return n, nil
+	}
+
+	if err := Decode(&n, src); err != nil {
+		return 0, err
+	}
+	if bits < 64 && n >= 1<<uint(bits) {
+		return 0, numberTypeError(dt, src)
+	}
+	return n, nil
+}
+
+var (
+	intTypes = map[int]reflect.Type{
+		0:  reflect.TypeOf(int(0)),
+		8:  reflect.TypeOf(int8(0)),
+		16: reflect.TypeOf(int16(0)),
+		32: reflect.TypeOf(int32(0)),
+		64: reflect.TypeOf(int64(0)),
+	}
+	uintTypes = map[int]reflect.Type{
+		0:  reflect.TypeOf(uint(0)),
+		8:  reflect.TypeOf(uint8(0)),
+		16: reflect.TypeOf(uint16(0)),
+		32: reflect.TypeOf(uint32(0)),
+		64: reflect.TypeOf(uint64(0)),
+	}
+)
+
+func numberTypeError(dt reflect.Type, src interface{}) error {
+	return &DecodeTypeError{
+		DestType: dt,
+		SrcType:  reflect.TypeOf(src),
+		Reason:   fmt.Sprintf("%v is not representable as %v", src, dt),
+	}
+}
+
+// DecodeFloat64 decodes src into a float64, it is used by generated decoders.
+func DecodeFloat64(src interface{}) (float64, error) {
+	switch x := src.(type) {
+	case float64:
+		return x, nil
+	case json.Number:
+		if f, err := strconv.ParseFloat(string(x), 64); err == nil {
+			return f, nil
+		}
+	}
+
+	var f float64
+	err := Decode(&f, src)
+	return f, err
+}
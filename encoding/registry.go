@@ -0,0 +1,118 @@
+package encoding
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// interfaceTypes maps a discriminator value to the concrete type used when
+// decoding a document into an interface type.
+type interfaceTypes struct {
+	field string
+	types map[string]reflect.Type
+}
+
+var typeRegistry struct {
+	sync.RWMutex
+	m map[reflect.Type]*interfaceTypes
+}
+
+// RegisterType registers the concrete type of v to be used when decoding a
+// document into a value of the interface type pointed to by iface. The type
+// is chosen by comparing the string stored in the documents field with value,
+// for example:
+//
+//	encoding.RegisterType((*Payment)(nil), "type", "credit_card", CreditCard{})
+//	encoding.RegisterType((*Payment)(nil), "type", "bank_transfer", &BankTransfer{})
+//
+// All types registered for an interface must use the same field. If v is a
+// pointer then the decoded value is stored as a pointer. The field is not
+// added when encoding so it should also be present in the concrete types.
+func RegisterType(iface interface{}, field, value string, v interface{}) {
+	it := reflect.TypeOf(iface)
+	if it == nil || it.Kind() != reflect.Ptr || it.Elem().Kind() != reflect.Interface {
+		panic("gorethink: RegisterType requires a pointer to an interface type")
+	}
+	it = it.Elem()
+	if it.NumMethod() == 0 {
+		panic("gorethink: RegisterType requires an interface type with methods")
+	}
+
+	t := reflect.TypeOf(v)
+	if t == nil || !t.Implements(it) {
+		panic(fmt.Sprintf("gorethink: type %v does not implement %v", t, it))
+	}
+
+	typeRegistry.Lock()
+	defer typeRegistry.Unlock()
+
+	types, ok := typeRegistry.m[it]
+	if !ok {
+		types = &interfaceTypes{field: field, types: make(map[string]reflect.Type)}
+		typeRegistry.m[it] = types
+	}
+	if types.field != field {
+		panic(fmt.Sprintf("gorethink: types for %v are already registered using field %q", it, types.field))
+	}
+	types.types[value] = t
+}
+
+// registeredType returns the concrete type registered for the interface type
+// it and the discriminator value found in sv. The boolean result is false if
+// no types are registered for it.
+func registeredType(it reflect.Type, sv reflect.Value) (reflect.Type, string, bool) {
+	typeRegistry.RLock()
+	types, ok := typeRegistry.m[it]
+	typeRegistry.RUnlock()
+	if !ok {
+		return nil, "", false
+	}
+
+	dv := sv.MapIndex(reflect.ValueOf(types.field).Convert(sv.Type().Key()))
+	if dv.IsValid() && dv.Kind() == reflect.Interface {
+		dv = dv.Elem()
+	}
+	if !dv.IsValid() || dv.Kind() != reflect.String {
+		return nil, fmt.Sprintf("missing string field %q", types.field), true
+	}
+
+	typeRegistry.RLock()
+	t, ok := types.types[dv.String()]
+	typeRegistry.RUnlock()
+	if !ok {
+		return nil, fmt.Sprintf("unknown %q value %q", types.field, dv.String()), true
+	}
+
+	return t, "", true
+}
+
+// newRegisteredInterfaceDecoder returns a decoder which decodes maps into
+// the concrete type registered for the interface type dt, falling back to
+// fallback if no types are registered.
+func newRegisteredInterfaceDecoder(dt, st reflect.Type, blank bool, fallback decoderFunc) decoderFunc {
+	return func(dv, sv reflect.Value) {
+		t, reason, ok := registeredType(dt, sv)
+		if !ok {
+			fallback(dv, sv)
+			return
+		}
+		if t == nil {
+			panic(&DecodeTypeError{
+				DestType: dt,
+				SrcType:  st,
+				Reason:   reason,
+			})
+		}
+
+		var v reflect.Value
+		if t.Kind() == reflect.Ptr {
+			v = reflect.New(t.Elem())
+			typeDecoder(t.Elem(), st, blank)(v.Elem(), sv)
+		} else {
+			v = reflect.New(t).Elem()
+			typeDecoder(t, st, blank)(v, sv)
+		}
+		dv.Set(v)
+	}
+}
@@ -17,6 +17,10 @@ const (
 	TagName     = "gorethink"
 	JSONTagName = "json"
 	RefTagName  = "gorethink_ref"
+
+	// nanosFieldSuffix is appended to the name of time fields with the nanos
+	// option to get the name of the field storing the nanoseconds
+	nanosFieldSuffix = "_nanos"
 )
 
 // tagOptions is the string following a comma in a struct field's
@@ -35,6 +35,23 @@ func isEmptyValue(v reflect.Value) bool {
 	return false
 }
 
+// embedsInterface returns true if t is a struct with an embedded field which
+// implements iface. Methods promoted from embedded fields (such as an embedded
+// time.Time) should not change how the rest of the struct is encoded.
+func embedsInterface(t reflect.Type, iface reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.Anonymous && (sf.Type.Implements(iface) || reflect.PtrTo(sf.Type).Implements(iface)) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func fieldByIndex(v reflect.Value, index []int) reflect.Value {
 	for _, i := range index {
 		if v.Kind() == reflect.Ptr {
@@ -49,6 +66,22 @@ func fieldByIndex(v reflect.Value, index []int) reflect.Value {
 	return v
 }
 
+// fieldByIndexNoAlloc is like fieldByIndex but returns an invalid value
+// instead of allocating when a nil embedded pointer is found.
+func fieldByIndexNoAlloc(v reflect.Value, index []int) reflect.Value {
+	for _, i := range index {
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}
+			}
+			v = v.Elem()
+		}
+		v = v.Field(i)
+	}
+
+	return v
+}
+
 func typeByIndex(t reflect.Type, index []int) reflect.Type {
 	for _, i := range index {
 		if t.Kind() == reflect.Ptr {
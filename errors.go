@@ -27,6 +27,15 @@ var (
 	ErrConnectionClosed = errors.New("gorethink: the connection is closed")
 	// ErrQueryTimeout is returned when query context deadline exceeded.
 	ErrQueryTimeout = errors.New("gorethink: query timeout")
+	// ErrFetchTimeout is returned when the server does not respond to a
+	// request for more cursor results within ConnectOpts.FetchTimeout.
+	ErrFetchTimeout = errors.New("gorethink: cursor fetch timeout")
+	// ErrUnixDiscoverHosts is returned by Connect when DiscoverHosts is used
+	// with unix domain socket connections.
+	ErrUnixDiscoverHosts = errors.New("gorethink: host discovery is not supported with unix sockets")
+	// ErrTooManyQueries is returned when a query cannot be started because
+	// ConnectOpts.MaxConcurrentQueries queries are already running.
+	ErrTooManyQueries = errors.New("gorethink: too many concurrent queries")
 )
 
 func printCarrots(t Term, frames []*p.Frame) string {
@@ -37,6 +46,18 @@ func printCarrots(t Term, frames []*p.Frame) string {
 		frame, frames = frames[0], []*p.Frame{}
 	}
 
+	// Copy the arguments so the query which caused the error is not modified
+	if t.args != nil {
+		t.args = append(termsList{}, t.args...)
+	}
+	if t.optArgs != nil {
+		optArgs := make(termsObj, len(t.optArgs))
+		for k, v := range t.optArgs {
+			optArgs[k] = v
+		}
+		t.optArgs = optArgs
+	}
+
 	for i, arg := range t.args {
 		if frame.GetPos() == int64(i) {
 			t.args[i] = Term{
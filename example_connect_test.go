@@ -1,6 +1,9 @@
 package gorethink_test
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
 	"log"
 	"os"
 
@@ -53,3 +56,32 @@ func ExampleConnect_cluster() {
 		log.Fatalln(err.Error())
 	}
 }
+
+func ExampleConnect_tls() {
+	var err error
+
+	caCert, err := ioutil.ReadFile("ca.pem")
+	if err != nil {
+		log.Fatalln(err.Error())
+	}
+	roots := x509.NewCertPool()
+	roots.AppendCertsFromPEM(caCert)
+
+	// Client certificates are only required if the server is configured to
+	// verify them
+	cert, err := tls.LoadX509KeyPair("client.pem", "client.key")
+	if err != nil {
+		log.Fatalln(err.Error())
+	}
+
+	session, err = r.Connect(r.ConnectOpts{
+		Address: url,
+		TLSConfig: &tls.Config{
+			RootCAs:      roots,
+			Certificates: []tls.Certificate{cert},
+		},
+	})
+	if err != nil {
+		log.Fatalln(err.Error())
+	}
+}
@@ -22,6 +22,29 @@ func ExampleTerm_Group() {
 	fmt.Print(res)
 }
 
+// Count the number of games played by each player.
+func ExampleTerm_Group_count() {
+	cur, err := DB("examples").Table("games").Group("player").Count().Run(session)
+	if err != nil {
+		fmt.Print(err)
+		return
+	}
+
+	var res []struct {
+		Group     string `gorethink:"group"`
+		Reduction int    `gorethink:"reduction"`
+	}
+	err = cur.All(&res)
+	if err != nil {
+		fmt.Print(err)
+		return
+	}
+
+	for _, group := range res {
+		fmt.Printf("%s: %d\n", group.Group, group.Reduction)
+	}
+}
+
 // Group games by the index type.
 func ExampleTerm_GroupByIndex() {
 	cur, err := DB("examples").Table("games").GroupByIndex("type").Run(session)
@@ -133,3 +156,29 @@ func ExampleTerm_Fold() {
 	// Output:
 	// a, b, c
 }
+
+// Compute a running total of a sequence.
+func ExampleTerm_Fold_emit() {
+	cur, err := Expr([]int{1, 2, 3, 4}).Fold(0, func(acc, n Term) Term {
+		return acc.Add(n)
+	}, FoldOpts{
+		Emit: func(acc, n, newAcc Term) []interface{} {
+			return []interface{}{newAcc}
+		},
+	}).Run(session)
+	if err != nil {
+		fmt.Print(err)
+		return
+	}
+
+	var res []int
+	err = cur.All(&res)
+	if err != nil {
+		fmt.Print(err)
+		return
+	}
+
+	fmt.Print(res)
+	// Output:
+	// [1 3 6 10]
+}
@@ -32,6 +32,51 @@ func ExampleBranch() {
 	// Jubilee is a hero
 }
 
+// Generate a sequence of numbers.
+func ExampleRange() {
+	var nums []int
+	err := Range(2, 5).ReadAll(&nums, session)
+	if err != nil {
+		fmt.Print(err)
+		return
+	}
+
+	fmt.Println(nums)
+
+	// Output:
+	// [2 3 4]
+}
+
+// Take the first numbers from an infinite sequence.
+func ExampleRange_infinite() {
+	var nums []int
+	err := Range().Limit(3).ReadAll(&nums, session)
+	if err != nil {
+		fmt.Print(err)
+		return
+	}
+
+	fmt.Println(nums)
+
+	// Output:
+	// [0 1 2]
+}
+
+// Generate a deterministic UUID from a string.
+func ExampleUUID() {
+	var uuid string
+	err := UUID("slava@example.com").ReadOne(&uuid, session)
+	if err != nil {
+		fmt.Print(err)
+		return
+	}
+
+	fmt.Println(uuid)
+
+	// Output:
+	// 90691cbc-b5ea-5826-ae98-951e30fc3b2d
+}
+
 // Return an error
 func ExampleError() {
 	err := Error("this is a runtime error").Exec(session)
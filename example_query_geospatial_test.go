@@ -0,0 +1,24 @@
+package gorethink
+
+import (
+	"fmt"
+)
+
+// Compute the distance between two points in kilometers.
+func ExampleDistance() {
+	var dist float64
+	err := Distance(
+		Point(-122.423246, 37.779388),
+		Point(-117.220406, 32.719464),
+		DistanceOpts{Unit: UnitKilometer},
+	).ReadOne(&dist, session)
+	if err != nil {
+		fmt.Print(err)
+		return
+	}
+
+	fmt.Printf("%.1f km", dist)
+
+	// Output:
+	// 734.1 km
+}
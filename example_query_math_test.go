@@ -0,0 +1,37 @@
+package gorethink
+
+import (
+	"fmt"
+)
+
+// Group numbers into buckets of ten.
+func ExampleTerm_Floor() {
+	var buckets []int
+	err := Expr([]float64{3, 17.5, 12, 29.9}).Map(func(n Term) Term {
+		return n.Div(10).Floor().Mul(10)
+	}).ReadAll(&buckets, session)
+	if err != nil {
+		fmt.Print(err)
+		return
+	}
+
+	fmt.Println(buckets)
+
+	// Output:
+	// [0 10 10 20]
+}
+
+// Round a number to the nearest integer.
+func ExampleRound() {
+	var n int
+	err := Round(-12.5).ReadOne(&n, session)
+	if err != nil {
+		fmt.Print(err)
+		return
+	}
+
+	fmt.Println(n)
+
+	// Output:
+	// -13
+}
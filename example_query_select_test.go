@@ -77,6 +77,33 @@ func ExampleTerm_GetAll_multiple() {
 	// Output: Superman
 }
 
+// Find all documents with a primary key greater than 2.
+func ExampleTerm_Between_maxVal() {
+	res, err := DB("examples").Table("heroes").Between(2, MaxVal, BetweenOpts{
+		LeftBound: BoundOpen,
+	}).OrderBy("id").Run(session)
+	if err != nil {
+		fmt.Print(err)
+		return
+	}
+	defer res.Close()
+
+	var heroes []map[string]interface{}
+	err = res.All(&heroes)
+	if err != nil {
+		fmt.Printf("Error scanning database result: %s", err)
+		return
+	}
+
+	for _, hero := range heroes {
+		fmt.Println(hero["name"])
+	}
+
+	// Output:
+	// Ant Man
+	// The Flash
+}
+
 // Find all document with an indexed value.
 func ExampleTerm_GetAll_optArgs() {
 	// Fetch the row from the database
@@ -54,6 +54,29 @@ func ExampleMap_multipleSequences() {
 	// [111 222 333 444]
 }
 
+// Multiply the elements of two sequences.
+func ExampleTerm_Map_multipleSequences() {
+	cur, err := Expr([]int{1, 2, 3}).Map([]int{10, 20, 30}, func(a, b Term) Term {
+		return a.Mul(b)
+	}).Run(session)
+	if err != nil {
+		fmt.Print(err)
+		return
+	}
+
+	var res []int
+	err = cur.All(&res)
+	if err != nil {
+		fmt.Print(err)
+		return
+	}
+
+	fmt.Print(res)
+
+	// Output:
+	// [10 40 90]
+}
+
 // Order all the posts using the index date.
 func ExampleTerm_OrderBy_index() {
 	cur, err := DB("examples").Table("posts").OrderBy(OrderByOpts{
@@ -0,0 +1,115 @@
+package gorethink
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	p "gopkg.in/gorethink/gorethink.v3/ql2"
+)
+
+// ConnectionEvent describes a connection passed to the ConnectOpts.OnConnect
+// and ConnectOpts.OnDisconnect hooks.
+type ConnectionEvent struct {
+	// Address is the address of the server the connection is to.
+	Address string
+	// Err is the error which caused the connection to be lost, nil if the
+	// connection was opened or was closed by the driver.
+	Err error
+}
+
+// QueryErrorEvent describes a query which failed because of a problem with
+// the connection to the server, it is passed to the ConnectOpts.OnQueryError
+// hook.
+type QueryErrorEvent struct {
+	// Address is the address of the server the query was sent to.
+	Address string
+	// Query is the query which failed.
+	Query Query
+	// Err is the error returned by the query.
+	Err error
+}
+
+const (
+	hookStateConnected int32 = iota + 1
+	hookStateDisconnected
+)
+
+// notifyConnect calls the OnConnect hook, if set.
+func (c *Connection) notifyConnect() {
+	atomic.StoreInt32(&c.hookState, hookStateConnected)
+	if c.opts.OnConnect != nil {
+		c.opts.OnConnect(ConnectionEvent{Address: c.address})
+	}
+}
+
+// notifyDisconnect calls the OnDisconnect hook, if set. The hook is called at
+// most once for each connection and only if the connection was opened
+// successfully.
+func (c *Connection) notifyDisconnect(err error) {
+	if !atomic.CompareAndSwapInt32(&c.hookState, hookStateConnected, hookStateDisconnected) || c.opts.OnDisconnect == nil {
+		return
+	}
+
+	c.opts.OnDisconnect(ConnectionEvent{
+		Address: c.address,
+		Err:     err,
+	})
+}
+
+// notifyQueryError calls the OnQueryError hook if set and the query failed
+// because of a connection error.
+func (c *Connection) notifyQueryError(q Query, err error) {
+	if c.opts.OnQueryError == nil || !isConnectionError(err) {
+		return
+	}
+
+	c.opts.OnQueryError(QueryErrorEvent{
+		Address: c.address,
+		Query:   q,
+		Err:     err,
+	})
+}
+
+// TraceEvent describes a message sent to or received from the server, it is
+// passed to the ConnectOpts.Trace hook and written to ConnectOpts.TraceWriter.
+type TraceEvent struct {
+	// Address is the address of the server.
+	Address string
+	// Received is false for queries sent to the server and true for
+	// responses received from the server.
+	Received bool
+	// Token is the token of the query the message belongs to.
+	Token int64
+	// QueryType is the type of a query sent to the server.
+	QueryType p.Query_QueryType
+	// ResponseType is the type of a response received from the server.
+	ResponseType p.Response_ResponseType
+	// Data is the serialized JSON of the query or response.
+	Data []byte
+	// Size is the number of bytes of the message, including its header.
+	Size int
+}
+
+func (e TraceEvent) String() string {
+	if e.Received {
+		return fmt.Sprintf("%s <- token=%d type=%s bytes=%d %s", e.Address, e.Token, e.ResponseType, e.Size, e.Data)
+	}
+
+	return fmt.Sprintf("%s -> token=%d type=%s bytes=%d %s", e.Address, e.Token, e.QueryType, e.Size, e.Data)
+}
+
+// tracing returns true if queries and responses should be traced.
+func (c *Connection) tracing() bool {
+	return c.opts.Trace != nil || c.opts.TraceWriter != nil
+}
+
+// trace passes the event to the Trace hook and TraceWriter, if set.
+func (c *Connection) trace(e TraceEvent) {
+	e.Address = c.address
+	if c.opts.Trace != nil {
+		c.opts.Trace(e)
+	}
+	if c.opts.TraceWriter != nil {
+		fmt.Fprintln(c.opts.TraceWriter, e.String())
+	}
+}
@@ -2,9 +2,11 @@ package gorethink
 
 import (
 	"fmt"
+	"strings"
 )
 
-// Host name and port of server
+// Host name and port of server. When connecting using a unix domain socket
+// Name holds the path of the socket and Port is zero.
 type Host struct {
 	Name string
 	Port int
@@ -18,7 +20,11 @@ func NewHost(name string, port int) Host {
 	}
 }
 
-// Returns host address (name:port)
+// Returns host address (name:port), or the socket path for unix domain sockets
 func (h Host) String() string {
+	if h.Port == 0 && strings.HasPrefix(h.Name, "/") {
+		return h.Name
+	}
+
 	return fmt.Sprintf("%s:%d", h.Name, h.Port)
 }
@@ -0,0 +1,137 @@
+package gorethink
+
+import (
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// HostSelector is used to select which host each query is sent to when the
+// session is connected to multiple hosts. A HostSelector can be set using
+// ConnectOpts.HostSelector, by default hosts are selected using an
+// epsilon-greedy algorithm which favours hosts which respond quickly.
+//
+// Hosts which have recently failed are not passed to Select unless every host
+// has failed.
+type HostSelector interface {
+	// Select returns the address of the host the next query should be sent
+	// to. hosts is never empty and is sorted by address.
+	Select(hosts []HostInfo) string
+	// Mark records the result of a query sent to a host, err is only non-nil
+	// if the connection to the host failed.
+	Mark(host string, err error, latency time.Duration)
+}
+
+// HostInfo describes a host which can be selected by a HostSelector.
+type HostInfo struct {
+	// Address is the address of the host (name:port).
+	Address string
+	// Stats contains statistics about the host's connection pool.
+	Stats PoolStats
+}
+
+// NewRoundRobinHostSelector returns a HostSelector which selects each host in
+// turn.
+func NewRoundRobinHostSelector() HostSelector {
+	return &roundRobinHostSelector{}
+}
+
+type roundRobinHostSelector struct {
+	next uint64
+}
+
+func (s *roundRobinHostSelector) Select(hosts []HostInfo) string {
+	i := atomic.AddUint64(&s.next, 1) - 1
+
+	return hosts[i%uint64(len(hosts))].Address
+}
+
+func (s *roundRobinHostSelector) Mark(host string, err error, latency time.Duration) {}
+
+// NewRandomHostSelector returns a HostSelector which selects a random host for
+// each query.
+func NewRandomHostSelector() HostSelector {
+	return randomHostSelector{}
+}
+
+type randomHostSelector struct{}
+
+func (randomHostSelector) Select(hosts []HostInfo) string {
+	return hosts[rand.Intn(len(hosts))].Address
+}
+
+func (randomHostSelector) Mark(host string, err error, latency time.Duration) {}
+
+// NewLeastConnectionsHostSelector returns a HostSelector which selects the host
+// with the fewest connections in use.
+func NewLeastConnectionsHostSelector() HostSelector {
+	return leastConnectionsHostSelector{}
+}
+
+type leastConnectionsHostSelector struct{}
+
+func (leastConnectionsHostSelector) Select(hosts []HostInfo) string {
+	selected := hosts[0]
+	for _, host := range hosts[1:] {
+		if host.Stats.InUse < selected.Stats.InUse {
+			selected = host
+		}
+	}
+
+	return selected.Address
+}
+
+func (leastConnectionsHostSelector) Mark(host string, err error, latency time.Duration) {}
+
+// NewLatencyHostSelector returns a HostSelector which selects the host with
+// the lowest average query latency. Hosts which have not yet been used are
+// selected first.
+func NewLatencyHostSelector() HostSelector {
+	return &latencyHostSelector{
+		latencies: make(map[string]time.Duration),
+	}
+}
+
+// latencyWeight is the weight given to the latest query when calculating the
+// moving average latency of a host.
+const latencyWeight = 0.2
+
+type latencyHostSelector struct {
+	mu        sync.Mutex
+	latencies map[string]time.Duration
+}
+
+func (s *latencyHostSelector) Select(hosts []HostInfo) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	selected := hosts[0].Address
+	min := time.Duration(-1)
+	for _, host := range hosts {
+		latency, ok := s.latencies[host.Address]
+		if !ok {
+			return host.Address
+		}
+		if min < 0 || latency < min {
+			selected = host.Address
+			min = latency
+		}
+	}
+
+	return selected
+}
+
+func (s *latencyHostSelector) Mark(host string, err error, latency time.Duration) {
+	if err != nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if avg, ok := s.latencies[host]; ok {
+		latency = time.Duration(latencyWeight*float64(latency) + (1-latencyWeight)*float64(avg))
+	}
+	s.latencies[host] = latency
+}
@@ -0,0 +1,62 @@
+package gorethink
+
+import (
+	"errors"
+	"time"
+
+	test "gopkg.in/check.v1"
+)
+
+var testHostInfos = []HostInfo{
+	{Address: "host1:28015", Stats: PoolStats{InUse: 3}},
+	{Address: "host2:28015", Stats: PoolStats{InUse: 1}},
+	{Address: "host3:28015", Stats: PoolStats{InUse: 2}},
+}
+
+func (s *RethinkSuite) TestHostSelectorRoundRobin(c *test.C) {
+	selector := NewRoundRobinHostSelector()
+
+	var selected []string
+	for i := 0; i < 4; i++ {
+		selected = append(selected, selector.Select(testHostInfos))
+	}
+
+	c.Assert(selected, test.DeepEquals, []string{
+		"host1:28015", "host2:28015", "host3:28015", "host1:28015",
+	})
+}
+
+func (s *RethinkSuite) TestHostSelectorRandom(c *test.C) {
+	selector := NewRandomHostSelector()
+
+	for i := 0; i < 10; i++ {
+		c.Assert(selector.Select(testHostInfos), test.Matches, "host[123]:28015")
+	}
+}
+
+func (s *RethinkSuite) TestHostSelectorLeastConnections(c *test.C) {
+	selector := NewLeastConnectionsHostSelector()
+
+	c.Assert(selector.Select(testHostInfos), test.Equals, "host2:28015")
+}
+
+func (s *RethinkSuite) TestHostSelectorLatency(c *test.C) {
+	selector := NewLatencyHostSelector()
+
+	// Unused hosts are selected first
+	c.Assert(selector.Select(testHostInfos), test.Equals, "host1:28015")
+	selector.Mark("host1:28015", nil, 10*time.Millisecond)
+	c.Assert(selector.Select(testHostInfos), test.Equals, "host2:28015")
+	selector.Mark("host2:28015", nil, 5*time.Millisecond)
+	c.Assert(selector.Select(testHostInfos), test.Equals, "host3:28015")
+	selector.Mark("host3:28015", nil, 20*time.Millisecond)
+
+	c.Assert(selector.Select(testHostInfos), test.Equals, "host2:28015")
+
+	// Errors do not affect the average latency
+	selector.Mark("host2:28015", errors.New("connection error"), time.Second)
+	c.Assert(selector.Select(testHostInfos), test.Equals, "host2:28015")
+
+	selector.Mark("host2:28015", nil, 100*time.Millisecond)
+	c.Assert(selector.Select(testHostInfos), test.Equals, "host1:28015")
+}
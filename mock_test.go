@@ -193,10 +193,10 @@ func (s *RethinkSuite) TestMockQueriesWithFuncs(c *test.C) {
 	mock.On(Expr([]int{2}).Map(func(row Term) interface{} {
 		return row.Add(1)
 	})).Return([]int{3}, nil).Times(2)
-	mock.On(Expr([]int{4}).Map(func(row1, row2 Term) interface{} {
+	mock.On(Expr([]int{4}).Map([]int{0}, func(row1, row2 Term) interface{} {
 		return row1.Add(1)
 	})).Return([]int{5}, nil).Times(1)
-	mock.On(Expr([]int{9}).Map(func(row1, row2 Term) interface{} {
+	mock.On(Expr([]int{9}).Map([]int{0}, func(row1, row2 Term) interface{} {
 		return row2.Add(1)
 	})).Return([]int{10}, nil).Times(1)
 
@@ -225,7 +225,7 @@ func (s *RethinkSuite) TestMockQueriesWithFuncs(c *test.C) {
 	c.Assert(response, jsonEquals, []int{3})
 
 	// Query 3
-	res, err = Expr([]int{4}).Map(func(row1, row2 Term) interface{} {
+	res, err = Expr([]int{4}).Map([]int{0}, func(row1, row2 Term) interface{} {
 		return row1.Add(1)
 	}).Run(mock)
 	c.Assert(err, test.IsNil)
@@ -236,7 +236,7 @@ func (s *RethinkSuite) TestMockQueriesWithFuncs(c *test.C) {
 	c.Assert(response, jsonEquals, []int{5})
 
 	// Query 5
-	res, err = Expr([]int{9}).Map(func(row1, row2 Term) interface{} {
+	res, err = Expr([]int{9}).Map([]int{0}, func(row1, row2 Term) interface{} {
 		return row2.Add(1)
 	}).Run(mock)
 	c.Assert(err, test.IsNil)
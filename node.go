@@ -4,7 +4,6 @@ import (
 	"sync"
 
 	"golang.org/x/net/context"
-	p "gopkg.in/gorethink/gorethink.v3/ql2"
 )
 
 // Node represents a database server in the cluster
@@ -49,9 +48,13 @@ func (n *Node) Close(optArgs ...CloseOpts) error {
 		return nil
 	}
 
-	if len(optArgs) >= 1 {
-		if optArgs[0].NoReplyWait {
-			n.NoReplyWait()
+	if len(optArgs) >= 1 && n.pool != nil {
+		opts := optArgs[0]
+		if opts.Drain {
+			n.pool.drain(opts.ctx)
+		}
+		if opts.NoReplyWait || opts.Drain {
+			n.pool.NoReplyWait(opts.ctx)
 		}
 	}
 
@@ -80,13 +83,22 @@ func (n *Node) SetMaxOpenConns(openConns int) {
 	n.pool.SetMaxOpenConns(openConns)
 }
 
+// Stats returns statistics about the node's connection pool.
+func (n *Node) Stats() PoolStats {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	if n.pool == nil {
+		return PoolStats{}
+	}
+
+	return n.pool.Stats()
+}
+
 // NoReplyWait ensures that previous queries with the noreply flag have been
-// processed by the server. Note that this guarantee only applies to queries
-// run on the given connection
+// processed by the server, on every connection in the node's pool.
 func (n *Node) NoReplyWait() error {
-	return n.pool.Exec(nil, Query{ // nil = connection opts' timeout
-		Type: p.Query_NOREPLY_WAIT,
-	})
+	return n.pool.NoReplyWait(nil) // nil = connection opts' timeout
 }
 
 // Query executes a ReQL query using this nodes connection pool.
@@ -109,13 +121,19 @@ func (n *Node) Exec(ctx context.Context, q Query) (err error) {
 
 // Server returns the server name and server UUID being used by a connection.
 func (n *Node) Server() (ServerResponse, error) {
-	var response ServerResponse
+	info, err := n.ServerInfo()
+	return info.serverResponse(), err
+}
+
+// ServerInfo returns information about the server being used by a connection.
+func (n *Node) ServerInfo() (ServerInfo, error) {
+	var response ServerInfo
 
 	if n.Closed() {
 		return response, ErrInvalidNode
 	}
 
-	return n.pool.Server()
+	return n.pool.ServerInfo()
 }
 
 type nodeStatus struct {
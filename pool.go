@@ -2,12 +2,15 @@ package gorethink
 
 import (
 	"errors"
-	"fmt"
-	"net"
 	"sync"
+	"time"
 
 	"golang.org/x/net/context"
-	"gopkg.in/fatih/pool.v2"
+)
+
+const (
+	defaultMaxIdleConns = 2
+	defaultPingTimeout  = 5 * time.Second
 )
 
 var (
@@ -19,14 +22,96 @@ type Pool struct {
 	host Host
 	opts *ConnectOpts
 
-	pool pool.Pool
+	mu         sync.Mutex // protects following fields
+	closed     bool
+	idle       []*poolConn
+	active     []*poolConn
+	numOpen    int
+	numDialing int
+	maxOpen    int
+	maxIdle    int
+	minIdle    int
+	maxQueries int
+	requests   []chan poolConnRequest
+	cleaner    chan struct{}
+	pinger     chan struct{}
+	stats      PoolStats
+}
+
+// PoolStats contains statistics about the connections of a connection pool.
+type PoolStats struct {
+	// MaxOpenConnections is the maximum number of open connections, zero if
+	// the number of connections is not limited.
+	MaxOpenConnections int
+
+	// OpenConnections is the number of open connections, both in use and idle.
+	OpenConnections int
+	// InUse is the number of connections currently in use.
+	InUse int
+	// Idle is the number of idle connections.
+	Idle int
+	// Dialing is the number of connections which are being opened, these are
+	// counted in OpenConnections but not InUse or Idle.
+	Dialing int
+
+	// WaitCount is the total number of times a query waited for a connection.
+	WaitCount int64
+	// WaitDuration is the total time spent waiting for a connection.
+	WaitDuration time.Duration
+	// ErrorClosed is the total number of connections closed due to errors.
+	ErrorClosed int64
+	// MaxIdleClosed is the total number of connections closed due to MaxIdle.
+	MaxIdleClosed int64
+	// IdleTimeoutClosed is the total number of connections closed due to
+	// IdleTimeout.
+	IdleTimeoutClosed int64
+	// MaxLifetimeClosed is the total number of connections closed due to
+	// MaxConnLifetime.
+	MaxLifetimeClosed int64
+}
+
+// add returns the sum of both sets of statistics.
+func (s PoolStats) add(o PoolStats) PoolStats {
+	s.MaxOpenConnections += o.MaxOpenConnections
+	s.OpenConnections += o.OpenConnections
+	s.InUse += o.InUse
+	s.Idle += o.Idle
+	s.Dialing += o.Dialing
+	s.WaitCount += o.WaitCount
+	s.WaitDuration += o.WaitDuration
+	s.ErrorClosed += o.ErrorClosed
+	s.MaxIdleClosed += o.MaxIdleClosed
+	s.IdleTimeoutClosed += o.IdleTimeoutClosed
+	s.MaxLifetimeClosed += o.MaxLifetimeClosed
+
+	return s
+}
+
+// poolConn wraps a connection with the information needed by the pool to
+// decide when the connection should be closed.
+type poolConn struct {
+	*Connection
+
+	createdAt  time.Time
+	returnedAt time.Time
+	// queries is the number of queries currently using the connection
+	queries int
+}
 
-	mu     sync.RWMutex // protects following fields
-	closed bool
+// poolConnRequest is sent to a goroutine waiting for a connection when a
+// connection is released or could not be opened.
+type poolConnRequest struct {
+	conn *poolConn
+	err  error
 }
 
 // NewPool creates a new connection pool for the given host
 func NewPool(host Host, opts *ConnectOpts) (*Pool, error) {
+	maxOpen := opts.MaxOpen
+	if maxOpen < 0 {
+		maxOpen = 0
+	}
+
 	initialCap := opts.InitialCap
 	if initialCap <= 0 {
 		// Fallback to MaxIdle if InitialCap is zero, this should be removed
@@ -34,38 +119,86 @@ func NewPool(host Host, opts *ConnectOpts) (*Pool, error) {
 		initialCap = opts.MaxIdle
 	}
 
-	maxOpen := opts.MaxOpen
-	if maxOpen <= 0 {
-		maxOpen = 2
+	maxIdle := opts.MaxIdle
+	if maxIdle <= 0 {
+		maxIdle = defaultMaxIdleConns
+		if maxOpen > 0 {
+			maxIdle = maxOpen
+		}
+	}
+	if initialCap > maxIdle {
+		maxIdle = initialCap
+	}
+	if maxOpen > 0 && maxIdle > maxOpen {
+		maxIdle = maxOpen
+	}
+
+	minIdle := opts.MinIdle
+	if minIdle < 0 {
+		minIdle = 0
+	}
+	if minIdle > maxIdle {
+		minIdle = maxIdle
+	}
+
+	maxQueries := opts.MaxQueriesPerConn
+	if maxQueries <= 0 {
+		maxQueries = 1
+	}
+
+	p := &Pool{
+		host:       host,
+		opts:       opts,
+		maxOpen:    maxOpen,
+		maxIdle:    maxIdle,
+		minIdle:    minIdle,
+		maxQueries: maxQueries,
 	}
 
-	p, err := pool.NewChannelPool(initialCap, maxOpen, func() (net.Conn, error) {
+	// Create the initial connections
+	for i := 0; i < initialCap && i < maxIdle; i++ {
 		conn, err := NewConnection(host.String(), opts)
 		if err != nil {
+			p.Close()
 			return nil, err
 		}
 
-		return conn, err
-	})
-	if err != nil {
-		return nil, err
+		p.numOpen++
+		p.idle = append(p.idle, newPoolConn(conn))
+	}
+
+	if interval := p.cleanerInterval(); interval > 0 {
+		p.cleaner = make(chan struct{})
+		go p.cleanIdle(interval, p.cleaner)
+	}
+	if interval := opts.PingInterval; interval > 0 {
+		p.pinger = make(chan struct{})
+		go p.pingIdle(interval, p.pinger)
 	}
 
-	return &Pool{
-		pool: p,
-		host: host,
-		opts: opts,
-	}, nil
+	return p, nil
+}
+
+func newPoolConn(conn *Connection) *poolConn {
+	now := time.Now()
+
+	return &poolConn{
+		Connection: conn,
+		createdAt:  now,
+		returnedAt: now,
+	}
 }
 
 // Ping verifies a connection to the database is still alive,
 // establishing a connection if necessary.
 func (p *Pool) Ping() error {
-	_, pc, err := p.conn()
+	pc, err := p.conn(nil)
 	if err != nil {
 		return err
 	}
-	return pc.Close()
+	p.putConn(pc)
+
+	return nil
 }
 
 // Close closes the database, releasing any open resources.
@@ -73,43 +206,481 @@ func (p *Pool) Ping() error {
 // It is rare to Close a Pool, as the Pool handle is meant to be
 // long-lived and shared between many goroutines.
 func (p *Pool) Close() error {
-	p.mu.RLock()
-	defer p.mu.RUnlock()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
 	if p.closed {
 		return nil
 	}
+	p.closed = true
 
-	p.pool.Close()
+	if p.cleaner != nil {
+		close(p.cleaner)
+	}
+	if p.pinger != nil {
+		close(p.pinger)
+	}
+
+	for _, pc := range p.idle {
+		pc.Close()
+		p.numOpen--
+	}
+	p.idle = nil
+
+	// Any goroutines waiting for a connection will fail, connections which
+	// are in use are closed once they are released
+	for _, req := range p.requests {
+		req <- poolConnRequest{err: errPoolClosed}
+	}
+	p.requests = nil
 
 	return nil
 }
 
-func (p *Pool) conn() (*Connection, *pool.PoolConn, error) {
-	p.mu.RLock()
-	defer p.mu.RUnlock()
+// conn returns a connection from the pool, if there are no idle connections
+// then a connection which is already in use is shared if MaxQueriesPerConn
+// allows it, otherwise a new connection is opened. If the maximum number of
+// connections are already open then conn waits for a connection to be
+// released, waiting goroutines are given connections in the order they
+// started waiting.
+func (p *Pool) conn(ctx context.Context) (*poolConn, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	p.mu.Lock()
 
 	if p.closed {
-		return nil, nil, errPoolClosed
+		p.mu.Unlock()
+		return nil, errPoolClosed
+	}
+
+	// Reuse an idle connection if one is available
+	now := time.Now()
+	for len(p.idle) > 0 {
+		pc := p.idle[0]
+		p.idle = p.idle[1:]
+
+		if p.closeExpiredLocked(pc, now, len(p.idle) >= p.minIdle) {
+			continue
+		}
+
+		p.acquireLocked(pc)
+		p.mu.Unlock()
+
+		// Check connections which have been idle for a while still work
+		if after := p.opts.PingIdleAfter; after <= 0 || now.Sub(pc.returnedAt) < after {
+			return pc, nil
+		}
+		if err := p.pingConn(ctx, pc); err == nil {
+			return pc, nil
+		}
+		p.putConn(pc)
+		if ctx.Err() != nil {
+			return nil, contextError(ctx)
+		}
+
+		p.mu.Lock()
+		if p.closed {
+			p.mu.Unlock()
+			return nil, errPoolClosed
+		}
+		now = time.Now()
+	}
+
+	// Share a connection which is already in use
+	if pc := p.sharedConnLocked(now); pc != nil {
+		p.acquireLocked(pc)
+		p.mu.Unlock()
+		return pc, nil
+	}
+
+	// Wait for a connection to be released if the pool is exhausted
+	if p.maxOpen > 0 && p.numOpen >= p.maxOpen {
+		req := make(chan poolConnRequest, 1)
+		p.requests = append(p.requests, req)
+		p.stats.WaitCount++
+		p.mu.Unlock()
+
+		start := time.Now()
+		defer p.recordWait(start)
+
+		select {
+		case <-ctx.Done():
+			p.mu.Lock()
+			for i, r := range p.requests {
+				if r == req {
+					p.requests = append(p.requests[:i], p.requests[i+1:]...)
+					break
+				}
+			}
+			p.mu.Unlock()
+
+			// A connection may have been sent before the request was removed
+			select {
+			case resp := <-req:
+				if resp.conn != nil {
+					p.putConn(resp.conn)
+				}
+			default:
+			}
+
+			return nil, contextError(ctx)
+		case resp := <-req:
+			return resp.conn, resp.err
+		}
 	}
 
-	nc, err := p.pool.Get()
+	p.numOpen++
+	p.numDialing++
+	p.mu.Unlock()
+
+	conn, err := NewConnection(p.host.String(), p.opts)
 	if err != nil {
-		return nil, nil, err
+		p.mu.Lock()
+		p.numOpen--
+		p.numDialing--
+		p.openForRequestLocked()
+		p.mu.Unlock()
+
+		return nil, err
+	}
+
+	pc := newPoolConn(conn)
+	p.mu.Lock()
+	p.numDialing--
+	p.acquireLocked(pc)
+	p.mu.Unlock()
+
+	return pc, nil
+}
+
+// contextError returns the error returned when ctx is done before a connection
+// is available, ErrQueryTimeout if the deadline was exceeded otherwise the
+// error of the context.
+func contextError(ctx context.Context) error {
+	if err := ctx.Err(); err != context.DeadlineExceeded {
+		return err
+	}
+
+	return ErrQueryTimeout
+}
+
+// acquireLocked records that a query is using the connection.
+func (p *Pool) acquireLocked(pc *poolConn) {
+	pc.queries++
+	if pc.queries == 1 {
+		p.active = append(p.active, pc)
+	}
+}
+
+// sharedConnLocked returns the connection in use by the fewest queries which
+// can be used by another query, nil if there is no such connection.
+func (p *Pool) sharedConnLocked(now time.Time) *poolConn {
+	var shared *poolConn
+	for _, pc := range p.active {
+		if !p.canShareLocked(pc, now) {
+			continue
+		}
+		if shared == nil || pc.queries < shared.queries {
+			shared = pc
+		}
+	}
+
+	return shared
+}
+
+// canShareLocked returns true if another query can use the connection.
+func (p *Pool) canShareLocked(pc *poolConn, now time.Time) bool {
+	return pc.queries < p.maxQueries && !pc.isBad() && !p.lifetimeExpired(pc, now)
+}
+
+// serveRequestsLocked gives the connection to waiting goroutines until it is
+// in use by MaxQueriesPerConn queries.
+func (p *Pool) serveRequestsLocked(pc *poolConn) {
+	for len(p.requests) > 0 && pc.queries < p.maxQueries {
+		req := p.requests[0]
+		p.requests = p.requests[1:]
+
+		p.acquireLocked(pc)
+		req <- poolConnRequest{conn: pc}
 	}
+}
 
-	pc, ok := nc.(*pool.PoolConn)
-	if !ok {
-		// This should never happen!
-		return nil, nil, fmt.Errorf("Invalid connection in pool")
+// putConn releases a connection used by a query. Once the connection is no
+// longer used by any queries it is returned to the pool.
+func (p *Pool) putConn(pc *poolConn) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	pc.queries--
+	if pc.queries > 0 {
+		// The connection is still in use so it can only be shared
+		if !p.closed && p.canShareLocked(pc, time.Now()) {
+			p.serveRequestsLocked(pc)
+		}
+		return
 	}
 
-	conn, ok := pc.Conn.(*Connection)
-	if !ok {
-		// This should never happen!
-		return nil, nil, fmt.Errorf("Invalid connection in pool")
+	for i, active := range p.active {
+		if active == pc {
+			p.active = append(p.active[:i], p.active[i+1:]...)
+			break
+		}
 	}
 
-	return conn, pc, nil
+	p.returnConnLocked(pc)
+}
+
+// returnConnLocked returns a connection which is not being used to the pool.
+// Bad or expired connections are closed, otherwise the connection is given to
+// waiting goroutines or stored as an idle connection.
+func (p *Pool) returnConnLocked(pc *poolConn) {
+	if p.closed {
+		p.closeConnLocked(pc)
+		return
+	}
+	if pc.isBad() {
+		p.stats.ErrorClosed++
+		p.closeConnLocked(pc)
+		return
+	}
+	if p.lifetimeExpired(pc, time.Now()) {
+		p.stats.MaxLifetimeClosed++
+		p.closeConnLocked(pc)
+		return
+	}
+
+	if len(p.requests) > 0 {
+		p.serveRequestsLocked(pc)
+		return
+	}
+
+	if len(p.idle) < p.maxIdle {
+		pc.returnedAt = time.Now()
+		p.idle = append(p.idle, pc)
+		return
+	}
+
+	p.stats.MaxIdleClosed++
+	p.closeConnLocked(pc)
+}
+
+// pingConn checks the connection can still be used, the connection is marked
+// as bad if the server does not respond.
+func (p *Pool) pingConn(ctx context.Context, pc *poolConn) error {
+	timeout := p.opts.Timeout
+	if timeout <= 0 {
+		timeout = defaultPingTimeout
+	}
+
+	pingCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := pc.noreplyWait(pingCtx)
+	if err != nil && ctx.Err() == nil {
+		Log.Warnf("Error pinging idle connection: %s", err.Error())
+		pc.markBad()
+	}
+
+	return err
+}
+
+// closeExpiredLocked closes the idle connection and returns true if it has
+// expired or is no longer usable. Connections which have only exceeded
+// IdleTimeout are kept unless prune is true.
+func (p *Pool) closeExpiredLocked(pc *poolConn, now time.Time, prune bool) bool {
+	if pc.isBad() {
+		p.stats.ErrorClosed++
+	} else if p.lifetimeExpired(pc, now) {
+		p.stats.MaxLifetimeClosed++
+	} else if prune && p.idleExpired(pc, now) {
+		p.stats.IdleTimeoutClosed++
+	} else {
+		return false
+	}
+
+	p.closeConnLocked(pc)
+	return true
+}
+
+// recordWait records the time spent waiting for a connection.
+func (p *Pool) recordWait(start time.Time) {
+	p.mu.Lock()
+	p.stats.WaitDuration += time.Since(start)
+	p.mu.Unlock()
+}
+
+// Stats returns statistics about the pool's connections.
+func (p *Pool) Stats() PoolStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stats := p.stats
+	stats.MaxOpenConnections = p.maxOpen
+	stats.OpenConnections = p.numOpen
+	stats.Idle = len(p.idle)
+	stats.Dialing = p.numDialing
+	stats.InUse = p.numOpen - p.numDialing - len(p.idle)
+
+	return stats
+}
+
+// closeConnLocked closes a connection owned by the pool and, as this frees
+// up space in the pool, opens a new connection for any waiting goroutine.
+func (p *Pool) closeConnLocked(pc *poolConn) {
+	pc.Close()
+	p.numOpen--
+	p.openForRequestLocked()
+}
+
+// openForRequestLocked opens a new connection in the background if there are
+// goroutines waiting for a connection and the pool is not full. If the
+// connection cannot be opened the first waiting goroutine is given the error
+// and another connection is opened for the remaining goroutines.
+func (p *Pool) openForRequestLocked() {
+	if p.closed || len(p.requests) == 0 {
+		return
+	}
+	if p.maxOpen > 0 && p.numOpen >= p.maxOpen {
+		return
+	}
+
+	p.numOpen++
+	p.numDialing++
+	go func() {
+		conn, err := NewConnection(p.host.String(), p.opts)
+
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		p.numDialing--
+		if err != nil {
+			p.numOpen--
+			if len(p.requests) > 0 {
+				req := p.requests[0]
+				p.requests = p.requests[1:]
+				req <- poolConnRequest{err: err}
+			}
+			p.openForRequestLocked()
+			return
+		}
+
+		p.returnConnLocked(newPoolConn(conn))
+	}()
+}
+
+// lifetimeExpired returns true if the connection has been open for longer
+// than MaxConnLifetime.
+func (p *Pool) lifetimeExpired(pc *poolConn, now time.Time) bool {
+	lifetime := p.opts.MaxConnLifetime
+
+	return lifetime > 0 && now.Sub(pc.createdAt) >= lifetime
+}
+
+// cleanerInterval returns how often idle connections should be checked for
+// expiry, zero if connections never expire. Unless IdleCheckInterval is set
+// this is the smaller of IdleTimeout and MaxConnLifetime.
+func (p *Pool) cleanerInterval() time.Duration {
+	if p.opts.IdleTimeout <= 0 && p.opts.MaxConnLifetime <= 0 {
+		return 0
+	}
+	if p.opts.IdleCheckInterval > 0 {
+		return p.opts.IdleCheckInterval
+	}
+
+	interval := p.opts.IdleTimeout
+	if lifetime := p.opts.MaxConnLifetime; lifetime > 0 && (interval <= 0 || lifetime < interval) {
+		interval = lifetime
+	}
+	if interval > 0 && interval < time.Second {
+		interval = time.Second
+	}
+
+	return interval
+}
+
+// cleanIdle periodically closes idle connections which have expired until
+// the stop channel is closed. Connections which have been idle for longer than
+// IdleTimeout are closed, oldest first, until MinIdle connections remain.
+func (p *Pool) cleanIdle(interval time.Duration, stop chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		p.mu.Lock()
+		now := time.Now()
+		prunable := len(p.idle) - p.minIdle
+		idle := p.idle[:0]
+		for _, pc := range p.idle {
+			if p.closeExpiredLocked(pc, now, prunable > 0) {
+				prunable--
+				continue
+			}
+			idle = append(idle, pc)
+		}
+		p.idle = idle
+		p.mu.Unlock()
+	}
+}
+
+// pingIdle periodically pings connections which have been idle for at least
+// interval until the stop channel is closed. Connections which fail to respond
+// within interval are closed.
+func (p *Pool) pingIdle(interval time.Duration, stop chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		// Remove the connections from the idle list while they are pinged so
+		// they are not used by queries
+		p.mu.Lock()
+		now := time.Now()
+		var ping []*poolConn
+		idle := p.idle[:0]
+		for _, pc := range p.idle {
+			if now.Sub(pc.returnedAt) >= interval {
+				p.acquireLocked(pc)
+				ping = append(ping, pc)
+				continue
+			}
+			idle = append(idle, pc)
+		}
+		p.idle = idle
+		p.mu.Unlock()
+
+		for _, pc := range ping {
+			ctx, cancel := context.WithTimeout(context.Background(), interval)
+			err := pc.noreplyWait(ctx)
+			cancel()
+			if err != nil {
+				Log.Warnf("Error pinging idle connection: %s", err.Error())
+				pc.markBad()
+			}
+
+			p.putConn(pc)
+		}
+	}
+}
+
+// idleExpired returns true if the connection has been idle for longer than
+// IdleTimeout.
+func (p *Pool) idleExpired(pc *poolConn, now time.Time) bool {
+	timeout := p.opts.IdleTimeout
+
+	return timeout > 0 && now.Sub(pc.returnedAt) >= timeout
 }
 
 // SetInitialPoolCap sets the initial capacity of the connection pool.
@@ -138,64 +709,130 @@ func (p *Pool) SetMaxOpenConns(n int) {
 
 // Exec executes a query without waiting for any response.
 func (p *Pool) Exec(ctx context.Context, q Query) error {
-	c, pc, err := p.conn()
+	pc, err := p.conn(ctx)
 	if err != nil {
 		return err
 	}
-	defer pc.Close()
+	defer p.putConn(pc)
 
-	_, _, err = c.Query(ctx, q)
-
-	if c.isBad() {
-		pc.MarkUnusable()
-	}
+	_, _, err = pc.Query(ctx, q)
 
 	return err
 }
 
 // Query executes a query and waits for the response
 func (p *Pool) Query(ctx context.Context, q Query) (*Cursor, error) {
-	c, pc, err := p.conn()
+	pc, err := p.conn(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	_, cursor, err := c.Query(ctx, q)
+	_, cursor, err := pc.Query(ctx, q)
+	if err != nil || cursor == nil {
+		p.putConn(pc)
+		return cursor, err
+	}
+
+	cursor.releaseConn = releaseConn(p, pc)
+
+	return cursor, nil
+}
+
+// NoReplyWait ensures that previous queries with the noreply flag have been
+// processed by the server on every open connection in the pool.
+func (p *Pool) NoReplyWait(ctx context.Context) error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return errPoolClosed
+	}
+
+	// Acquire every connection so they are not closed while waiting, idle
+	// connections are removed from the idle list until they are released
+	conns := make([]*poolConn, 0, len(p.idle)+len(p.active))
+	conns = append(conns, p.idle...)
+	conns = append(conns, p.active...)
+	p.idle = nil
+	for _, pc := range conns {
+		p.acquireLocked(pc)
+	}
+	p.mu.Unlock()
 
-	if err == nil {
-		cursor.releaseConn = releaseConn(c, pc)
-	} else if c.isBad() {
-		pc.MarkUnusable()
+	var err error
+	for _, pc := range conns {
+		if connErr := pc.noreplyWait(ctx); connErr != nil && err == nil {
+			err = connErr
+		}
+		p.putConn(pc)
 	}
 
-	return cursor, err
+	return err
+}
+
+// drain stops any changefeeds using the pool's connections and waits until no
+// queries are using the connections or ctx is done.
+func (p *Pool) drain(ctx context.Context) {
+	p.mu.Lock()
+	var cursors []*Cursor
+	for _, pc := range p.active {
+		cursors = append(cursors, pc.openCursors()...)
+	}
+	p.mu.Unlock()
+
+	for _, cursor := range cursors {
+		if cursor.IsFeed() {
+			cursor.Close()
+		}
+	}
+
+	var done <-chan struct{}
+	if ctx != nil {
+		done = ctx.Done()
+	}
+
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		p.mu.Lock()
+		active := len(p.active)
+		p.mu.Unlock()
+
+		if active == 0 {
+			return
+		}
+
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+	}
 }
 
 // Server returns the server name and server UUID being used by a connection.
 func (p *Pool) Server() (ServerResponse, error) {
-	var response ServerResponse
+	info, err := p.ServerInfo()
+	return info.serverResponse(), err
+}
+
+// ServerInfo returns information about the server being used by a connection.
+func (p *Pool) ServerInfo() (ServerInfo, error) {
+	var response ServerInfo
 
-	c, pc, err := p.conn()
+	pc, err := p.conn(nil)
 	if err != nil {
 		return response, err
 	}
-	defer pc.Close()
+	defer p.putConn(pc)
 
-	response, err = c.Server()
-
-	if c.isBad() {
-		pc.MarkUnusable()
-	}
-
-	return response, err
+	return pc.ServerInfo()
 }
 
-func releaseConn(c *Connection, pc *pool.PoolConn) func() error {
+func releaseConn(p *Pool, pc *poolConn) func() error {
 	return func() error {
-		if c.isBad() {
-			pc.MarkUnusable()
-		}
+		p.putConn(pc)
 
-		return pc.Close()
+		return nil
 	}
 }
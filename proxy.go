@@ -0,0 +1,252 @@
+package gorethink
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	neturl "net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// proxyURL returns the URL of the proxy used to connect to the database, or
+// nil if connections should not use a proxy.
+func (o *ConnectOpts) proxyURL() (*neturl.URL, error) {
+	raw := o.ProxyURL
+	if raw == "" && o.ProxyFromEnvironment {
+		raw = os.Getenv("ALL_PROXY")
+		if raw == "" {
+			raw = os.Getenv("all_proxy")
+		}
+	}
+	if raw == "" {
+		return nil, nil
+	}
+
+	u, err := neturl.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("gorethink: invalid proxy URL: %s", err)
+	}
+
+	switch u.Scheme {
+	case "socks5", "socks5h", "http":
+		return u, nil
+	default:
+		return nil, fmt.Errorf("gorethink: unsupported proxy scheme %q", u.Scheme)
+	}
+}
+
+// dialProxy connects to address through the proxy, the proxy handshake must
+// complete within timeout if it is set.
+func dialProxy(nd *net.Dialer, proxy *neturl.URL, address string, timeout time.Duration) (net.Conn, error) {
+	proxyAddress := proxy.Host
+	if _, _, err := net.SplitHostPort(proxyAddress); err != nil {
+		// Use the default port for the proxy type
+		port := "1080"
+		if proxy.Scheme == "http" {
+			port = "80"
+		}
+		proxyAddress = net.JoinHostPort(strings.Trim(proxyAddress, "[]"), port)
+	}
+
+	conn, err := nd.Dial("tcp", proxyAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	if timeout > 0 {
+		conn.SetDeadline(time.Now().Add(timeout))
+	}
+	if proxy.Scheme == "http" {
+		err = httpConnect(conn, proxy, address)
+	} else {
+		err = socks5Connect(conn, proxy, address)
+	}
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	conn.SetDeadline(time.Time{})
+
+	return conn, nil
+}
+
+// httpConnect asks an HTTP proxy to open a tunnel to address using the
+// CONNECT method.
+func httpConnect(conn net.Conn, proxy *neturl.URL, address string) error {
+	req := &http.Request{
+		Method: "CONNECT",
+		URL:    &neturl.URL{Opaque: address},
+		Host:   address,
+		Header: make(http.Header),
+	}
+	if proxy.User != nil {
+		password, _ := proxy.User.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(proxy.User.Username() + ":" + password))
+		req.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+
+	if err := req.Write(conn); err != nil {
+		return err
+	}
+
+	// The server does not send anything until the driver starts the
+	// handshake so the reader cannot buffer any data after the response
+	resp, err := http.ReadResponse(bufio.NewReader(conn), req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("gorethink: proxy refused connection: %s", resp.Status)
+	}
+
+	return nil
+}
+
+const (
+	socks5Version          = 5
+	socks5AuthNone         = 0
+	socks5AuthPassword     = 2
+	socks5AuthNoAcceptable = 0xff
+	socks5CmdConnect       = 1
+	socks5AddrIPv4         = 1
+	socks5AddrDomain       = 3
+	socks5AddrIPv6         = 4
+)
+
+var socks5Errors = []string{
+	"",
+	"general failure",
+	"connection not allowed by ruleset",
+	"network unreachable",
+	"host unreachable",
+	"connection refused",
+	"TTL expired",
+	"command not supported",
+	"address type not supported",
+}
+
+// socks5Connect asks a SOCKS5 proxy to connect to address, as described in
+// RFC 1928 and RFC 1929.
+func socks5Connect(conn net.Conn, proxy *neturl.URL, address string) error {
+	host, portStr, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return fmt.Errorf("gorethink: invalid port %q", portStr)
+	}
+
+	// Negotiate the authentication method
+	methods := []byte{socks5AuthNone}
+	if proxy.User != nil {
+		methods = append(methods, socks5AuthPassword)
+	}
+	req := append([]byte{socks5Version, byte(len(methods))}, methods...)
+	if _, err := conn.Write(req); err != nil {
+		return err
+	}
+
+	resp := make([]byte, 2)
+	if _, err := io.ReadFull(conn, resp); err != nil {
+		return err
+	}
+	if resp[0] != socks5Version {
+		return fmt.Errorf("gorethink: unexpected SOCKS version %d", resp[0])
+	}
+
+	switch resp[1] {
+	case socks5AuthNone:
+	case socks5AuthPassword:
+		if proxy.User == nil {
+			return errors.New("gorethink: SOCKS5 proxy requires authentication")
+		}
+		username := proxy.User.Username()
+		password, _ := proxy.User.Password()
+		if len(username) > 255 || len(password) > 255 {
+			return errors.New("gorethink: SOCKS5 username or password is too long")
+		}
+
+		req = []byte{1, byte(len(username))}
+		req = append(req, username...)
+		req = append(req, byte(len(password)))
+		req = append(req, password...)
+		if _, err := conn.Write(req); err != nil {
+			return err
+		}
+		if _, err := io.ReadFull(conn, resp); err != nil {
+			return err
+		}
+		if resp[1] != 0 {
+			return errors.New("gorethink: SOCKS5 authentication failed")
+		}
+	case socks5AuthNoAcceptable:
+		return errors.New("gorethink: no acceptable SOCKS5 authentication methods")
+	default:
+		return fmt.Errorf("gorethink: unsupported SOCKS5 authentication method %d", resp[1])
+	}
+
+	// Request a connection to the address
+	req = []byte{socks5Version, socks5CmdConnect, 0}
+	if ip := net.ParseIP(host); ip == nil {
+		if len(host) > 255 {
+			return fmt.Errorf("gorethink: hostname %q is too long", host)
+		}
+		req = append(req, socks5AddrDomain, byte(len(host)))
+		req = append(req, host...)
+	} else if ip4 := ip.To4(); ip4 != nil {
+		req = append(req, socks5AddrIPv4)
+		req = append(req, ip4...)
+	} else {
+		req = append(req, socks5AddrIPv6)
+		req = append(req, ip.To16()...)
+	}
+	req = append(req, 0, 0)
+	binary.BigEndian.PutUint16(req[len(req)-2:], uint16(port))
+
+	if _, err := conn.Write(req); err != nil {
+		return err
+	}
+
+	// Read the reply, the bound address is not used
+	resp = make([]byte, 4)
+	if _, err := io.ReadFull(conn, resp); err != nil {
+		return err
+	}
+	if resp[1] != 0 {
+		reason := "unknown error"
+		if int(resp[1]) < len(socks5Errors) {
+			reason = socks5Errors[resp[1]]
+		}
+		return fmt.Errorf("gorethink: SOCKS5 proxy failed to connect: %s", reason)
+	}
+
+	var addrLen int
+	switch resp[3] {
+	case socks5AddrIPv4:
+		addrLen = net.IPv4len
+	case socks5AddrIPv6:
+		addrLen = net.IPv6len
+	case socks5AddrDomain:
+		l := make([]byte, 1)
+		if _, err := io.ReadFull(conn, l); err != nil {
+			return err
+		}
+		addrLen = int(l[0])
+	default:
+		return fmt.Errorf("gorethink: unexpected SOCKS5 address type %d", resp[3])
+	}
+
+	_, err = io.ReadFull(conn, make([]byte, addrLen+2))
+	return err
+}
@@ -0,0 +1,206 @@
+package gorethink
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	test "gopkg.in/check.v1"
+)
+
+// listenEcho starts a server which echoes everything it receives.
+func listenEcho(c *test.C) net.Listener {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, test.IsNil)
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				io.Copy(conn, conn)
+			}()
+		}
+	}()
+
+	return ln
+}
+
+// listenProxy starts a proxy which uses handshake to read the address each
+// client wants to connect to before forwarding the connection.
+func listenProxy(c *test.C, handshake func(conn net.Conn, r *bufio.Reader) string) net.Listener {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, test.IsNil)
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+
+				r := bufio.NewReader(conn)
+				address := handshake(conn, r)
+				if address == "" {
+					return
+				}
+
+				target, err := net.Dial("tcp", address)
+				if err != nil {
+					return
+				}
+				defer target.Close()
+
+				go io.Copy(target, r)
+				io.Copy(conn, target)
+			}()
+		}
+	}()
+
+	return ln
+}
+
+func socks5Handshake(conn net.Conn, r *bufio.Reader) string {
+	// Accept the greeting using the username/password method
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return ""
+	}
+	if _, err := io.ReadFull(r, make([]byte, header[1])); err != nil {
+		return ""
+	}
+	conn.Write([]byte{5, 2})
+
+	// Check the credentials
+	ver, _ := r.ReadByte()
+	ulen, _ := r.ReadByte()
+	username := make([]byte, ulen)
+	io.ReadFull(r, username)
+	plen, _ := r.ReadByte()
+	password := make([]byte, plen)
+	io.ReadFull(r, password)
+	if ver != 1 || string(username) != "user" || string(password) != "pass" {
+		conn.Write([]byte{1, 1})
+		return ""
+	}
+	conn.Write([]byte{1, 0})
+
+	// Read the connect request, only domain names are expected
+	req := make([]byte, 5)
+	if _, err := io.ReadFull(r, req); err != nil || req[3] != 3 {
+		return ""
+	}
+	host := make([]byte, req[4])
+	io.ReadFull(r, host)
+	port := make([]byte, 2)
+	io.ReadFull(r, port)
+
+	conn.Write([]byte{5, 0, 0, 1, 127, 0, 0, 1, 0, 0})
+
+	return net.JoinHostPort(string(host), strconv.Itoa(int(binary.BigEndian.Uint16(port))))
+}
+
+func httpConnectHandshake(conn net.Conn, r *bufio.Reader) string {
+	req, err := http.ReadRequest(r)
+	if err != nil || req.Method != "CONNECT" {
+		return ""
+	}
+
+	// Expect the credentials user:pass
+	if req.Header.Get("Proxy-Authorization") != "Basic dXNlcjpwYXNz" {
+		conn.Write([]byte("HTTP/1.1 407 Proxy Authentication Required\r\n\r\n"))
+		return ""
+	}
+	conn.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n"))
+
+	return req.Host
+}
+
+func assertEcho(c *test.C, conn net.Conn) {
+	defer conn.Close()
+
+	_, err := conn.Write([]byte("ping"))
+	c.Assert(err, test.IsNil)
+
+	buf := make([]byte, 4)
+	_, err = io.ReadFull(conn, buf)
+	c.Assert(err, test.IsNil)
+	c.Assert(string(buf), test.Equals, "ping")
+}
+
+func (s *RethinkSuite) TestProxySOCKS5(c *test.C) {
+	echo := listenEcho(c)
+	defer echo.Close()
+	proxy := listenProxy(c, socks5Handshake)
+	defer proxy.Close()
+
+	_, port, _ := net.SplitHostPort(echo.Addr().String())
+	opts := &ConnectOpts{ProxyURL: "socks5://user:pass@" + proxy.Addr().String()}
+	u, err := opts.proxyURL()
+	c.Assert(err, test.IsNil)
+
+	conn, err := dialProxy(&net.Dialer{}, u, net.JoinHostPort("localhost", port), time.Second)
+	c.Assert(err, test.IsNil)
+	assertEcho(c, conn)
+
+	// Connections with the wrong credentials fail
+	opts.ProxyURL = "socks5://user:wrong@" + proxy.Addr().String()
+	u, err = opts.proxyURL()
+	c.Assert(err, test.IsNil)
+
+	_, err = dialProxy(&net.Dialer{}, u, net.JoinHostPort("localhost", port), time.Second)
+	c.Assert(err, test.NotNil)
+}
+
+func (s *RethinkSuite) TestProxyHTTPConnect(c *test.C) {
+	echo := listenEcho(c)
+	defer echo.Close()
+	proxy := listenProxy(c, httpConnectHandshake)
+	defer proxy.Close()
+
+	opts := &ConnectOpts{ProxyURL: "http://user:pass@" + proxy.Addr().String()}
+	u, err := opts.proxyURL()
+	c.Assert(err, test.IsNil)
+
+	conn, err := dialProxy(&net.Dialer{}, u, echo.Addr().String(), time.Second)
+	c.Assert(err, test.IsNil)
+	assertEcho(c, conn)
+
+	// The proxy requires authentication
+	opts.ProxyURL = "http://" + proxy.Addr().String()
+	u, err = opts.proxyURL()
+	c.Assert(err, test.IsNil)
+
+	_, err = dialProxy(&net.Dialer{}, u, echo.Addr().String(), time.Second)
+	c.Assert(err, test.NotNil)
+}
+
+func (s *RethinkSuite) TestProxyURL(c *test.C) {
+	u, err := (&ConnectOpts{}).proxyURL()
+	c.Assert(err, test.IsNil)
+	c.Assert(u, test.IsNil)
+
+	_, err = (&ConnectOpts{ProxyURL: "ftp://proxy"}).proxyURL()
+	c.Assert(err, test.NotNil)
+
+	defer os.Setenv("ALL_PROXY", os.Getenv("ALL_PROXY"))
+	os.Setenv("ALL_PROXY", "socks5://proxy:1080")
+
+	u, err = (&ConnectOpts{}).proxyURL()
+	c.Assert(err, test.IsNil)
+	c.Assert(u, test.IsNil)
+
+	u, err = (&ConnectOpts{ProxyFromEnvironment: true}).proxyURL()
+	c.Assert(err, test.IsNil)
+	c.Assert(u.Host, test.Equals, "proxy:1080")
+}
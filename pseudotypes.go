@@ -2,7 +2,9 @@ package gorethink
 
 import (
 	"encoding/base64"
+	"encoding/json"
 	"math"
+	"reflect"
 	"strconv"
 	"time"
 
@@ -25,7 +27,7 @@ func convertPseudotype(obj map[string]interface{}, opts map[string]interface{})
 			}
 
 			if timeFormat == "native" {
-				return reqlTimeToNativeTime(obj["epoch_time"].(float64), obj["timezone"].(string))
+				return reqlTimeToNativeTime(normalizeNumbers(obj["epoch_time"], false).(float64), obj["timezone"].(string))
 			} else if timeFormat == "raw" {
 				return obj, nil
 			} else {
@@ -79,6 +81,9 @@ func convertPseudotype(obj map[string]interface{}, opts map[string]interface{})
 			}
 
 			if geometryFormat == "native" {
+				if coords, ok := obj["coordinates"]; ok {
+					obj["coordinates"] = normalizeNumbers(coords, false)
+				}
 				return reqlGeometryToNativeGeometry(obj)
 			} else if geometryFormat == "raw" {
 				return obj, nil
@@ -123,6 +128,35 @@ func recursivelyConvertPseudotype(obj interface{}, opts map[string]interface{})
 	return obj, nil
 }
 
+// normalizeNumbers replaces the json.Number values in v, which are used when
+// the number_format run option is not native. If integers is true integral
+// numbers are replaced with int64 values and other numbers with float64
+// values, otherwise all numbers are replaced with float64 values.
+func normalizeNumbers(v interface{}, integers bool) interface{} {
+	switch v := v.(type) {
+	case []interface{}:
+		for i, e := range v {
+			v[i] = normalizeNumbers(e, integers)
+		}
+	case map[string]interface{}:
+		for k, e := range v {
+			v[k] = normalizeNumbers(e, integers)
+		}
+	case json.Number:
+		if integers {
+			return parseNumber(v)
+		}
+		f, _ := v.Float64()
+		return f
+	case int64:
+		if !integers {
+			return float64(v)
+		}
+	}
+
+	return v
+}
+
 // Pseudo-type helper functions
 
 func reqlTimeToNativeTime(timestamp float64, timezone string) (time.Time, error) {
@@ -172,6 +206,9 @@ func reqlGroupedDataToMap(obj map[string]interface{}) (interface{}, error) {
 		ret := map[interface{}]interface{}{}
 		for _, v := range data.([]interface{}) {
 			v := v.([]interface{})
+			if v[0] != nil && !reflect.TypeOf(v[0]).Comparable() {
+				return nil, fmt.Errorf("group %v of type %T can not be used as a map key, use the slice group_format instead", v[0], v[0])
+			}
 			ret[v[0]] = v[1]
 		}
 		return ret, nil
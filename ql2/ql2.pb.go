@@ -478,14 +478,20 @@ const (
 	Term_GE  Term_TermType = 22
 	Term_NOT Term_TermType = 23
 	// ADD can either add two numbers or concatenate two arrays.
-	Term_ADD   Term_TermType = 24
-	Term_SUB   Term_TermType = 25
-	Term_MUL   Term_TermType = 26
-	Term_DIV   Term_TermType = 27
-	Term_MOD   Term_TermType = 28
-	Term_FLOOR Term_TermType = 183
-	Term_CEIL  Term_TermType = 184
-	Term_ROUND Term_TermType = 185
+	Term_ADD     Term_TermType = 24
+	Term_SUB     Term_TermType = 25
+	Term_MUL     Term_TermType = 26
+	Term_DIV     Term_TermType = 27
+	Term_MOD     Term_TermType = 28
+	Term_FLOOR   Term_TermType = 183
+	Term_CEIL    Term_TermType = 184
+	Term_ROUND   Term_TermType = 185
+	Term_BIT_AND Term_TermType = 191
+	Term_BIT_OR  Term_TermType = 192
+	Term_BIT_XOR Term_TermType = 193
+	Term_BIT_NOT Term_TermType = 194
+	Term_BIT_SAL Term_TermType = 195
+	Term_BIT_SAR Term_TermType = 196
 	// DATUM Array Ops
 	// Append a single element to the end of an array (like `snoc`).
 	Term_APPEND Term_TermType = 29
@@ -665,6 +671,10 @@ const (
 	Term_SYNC Term_TermType = 138
 	// Set global, database, or table-specific permissions
 	Term_GRANT Term_TermType = 188
+	// Sets the write hook on a table or overwrites it if it already exists.
+	Term_SET_WRITE_HOOK Term_TermType = 189
+	// Returns the write hook of a table.
+	Term_GET_WRITE_HOOK Term_TermType = 190
 	// * Secondary indexes OPs
 	// Creates a new secondary index with a particular name and definition.
 	Term_INDEX_CREATE Term_TermType = 75
@@ -885,6 +895,12 @@ var Term_TermType_name = map[int32]string{
 	183: "FLOOR",
 	184: "CEIL",
 	185: "ROUND",
+	191: "BIT_AND",
+	192: "BIT_OR",
+	193: "BIT_XOR",
+	194: "BIT_NOT",
+	195: "BIT_SAL",
+	196: "BIT_SAR",
 	29:  "APPEND",
 	80:  "PREPEND",
 	95:  "DIFFERENCE",
@@ -948,6 +964,8 @@ var Term_TermType_name = map[int32]string{
 	179: "REBALANCE",
 	138: "SYNC",
 	188: "GRANT",
+	189: "SET_WRITE_HOOK",
+	190: "GET_WRITE_HOOK",
 	75:  "INDEX_CREATE",
 	76:  "INDEX_DROP",
 	77:  "INDEX_LIST",
@@ -1065,6 +1083,12 @@ var Term_TermType_value = map[string]int32{
 	"FLOOR":              183,
 	"CEIL":               184,
 	"ROUND":              185,
+	"BIT_AND":            191,
+	"BIT_OR":             192,
+	"BIT_XOR":            193,
+	"BIT_NOT":            194,
+	"BIT_SAL":            195,
+	"BIT_SAR":            196,
 	"APPEND":             29,
 	"PREPEND":            80,
 	"DIFFERENCE":         95,
@@ -1128,6 +1152,8 @@ var Term_TermType_value = map[string]int32{
 	"REBALANCE":          179,
 	"SYNC":               138,
 	"GRANT":              188,
+	"SET_WRITE_HOOK":     189,
+	"GET_WRITE_HOOK":     190,
 	"INDEX_CREATE":       75,
 	"INDEX_DROP":         76,
 	"INDEX_LIST":         77,
@@ -1,10 +1,14 @@
 package gorethink
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"golang.org/x/net/context"
 	p "gopkg.in/gorethink/gorethink.v3/ql2"
@@ -21,6 +25,10 @@ type Query struct {
 	Term      *Term
 	Opts      map[string]interface{}
 	builtTerm interface{}
+
+	retryPolicy     *RetryPolicy
+	prefetchBatches int
+	disablePrefetch bool
 }
 
 func (q *Query) Build() []interface{} {
@@ -34,7 +42,7 @@ func (q *Query) Build() []interface{} {
 		opts := map[string]interface{}{}
 		for k, v := range q.Opts {
 			switch k {
-			case "geometry_format":
+			case "geometry_format", "number_format":
 			default:
 				opts[k] = v
 			}
@@ -55,6 +63,16 @@ type termsObj map[string]Term
 //
 // When built the term becomes a JSON array, for more information on the format
 // see http://rethinkdb.com/docs/writing-drivers/.
+//
+// Terms are immutable, every method returns a new Term and never modifies the
+// term it was called on or its arguments. This means a base query can be
+// stored in a variable and extended or run concurrently from multiple
+// goroutines, for example:
+//
+//     var activeUsers = r.Table("users").Filter(r.Row.Field("active"))
+//
+//     activeUsers.Limit(10).Run(session)
+//     activeUsers.OrderBy("name").Run(session)
 type Term struct {
 	name           string
 	rawQuery       bool
@@ -187,7 +205,163 @@ func (t Term) Build() (interface{}, error) {
 	return ret, nil
 }
 
-// String returns a string representation of the query tree
+// MarshalQuery returns the JSON encoding of the term exactly as it is sent to
+// the server as part of a query, including any optional arguments. Object keys
+// are sorted so the output is deterministic, which makes it useful for logging
+// queries and for testing how they are constructed, for example:
+//
+//     b, _ := r.Table("users").Get("alice").MarshalQuery()
+//     // [16,[[15,["users"]],"alice"]]
+//
+// Options passed to Run, such as the database, are not part of the term and
+// are not included. The result can be converted back to a term with RawQuery.
+func (t Term) MarshalQuery() ([]byte, error) {
+	built, err := t.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := json.Marshal(built)
+	if err != nil {
+		return nil, RQLDriverError{rqlError(fmt.Sprintf("Error building query: %s", err.Error()))}
+	}
+
+	return b, nil
+}
+
+// PreparedQuery is a query template which has been serialized by Prepare, the
+// values of its parameters are set using Bind. A PreparedQuery can be bound
+// concurrently from multiple goroutines.
+type PreparedQuery struct {
+	// segments contains the JSON before, between and after the parameters
+	segments [][]byte
+	params   []string
+}
+
+var paramMarkerRe = regexp.MustCompile(`"\\u0000gorethink_param_(\d+)\\u0000"`)
+
+// Prepare serializes a query template containing Param placeholders so that
+// it can be executed repeatedly with different parameters without rebuilding
+// and encoding the whole term each time. See Param for an example.
+func (t Term) Prepare() (*PreparedQuery, error) {
+	built, err := t.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	// Replace the parameters with markers which can be found in the JSON
+	var params []string
+	built = replaceParams(built, func(name queryParam) interface{} {
+		params = append(params, string(name))
+		return fmt.Sprintf("\x00gorethink_param_%d\x00", len(params)-1)
+	})
+
+	b, err := json.Marshal(built)
+	if err != nil {
+		return nil, RQLDriverError{rqlError(fmt.Sprintf("Error building query: %s", err.Error()))}
+	}
+
+	q := &PreparedQuery{}
+	last := 0
+	for _, m := range paramMarkerRe.FindAllSubmatchIndex(b, -1) {
+		i, _ := strconv.Atoi(string(b[m[2]:m[3]]))
+		q.segments = append(q.segments, b[last:m[0]])
+		q.params = append(q.params, params[i])
+		last = m[1]
+	}
+	q.segments = append(q.segments, b[last:])
+
+	return q, nil
+}
+
+// Bind returns a term which runs the prepared query with the given parameter
+// values. An error is returned when the term is run if a parameter is missing,
+// params contains a name which is not used by the query or a value cannot be
+// encoded.
+func (q *PreparedQuery) Bind(params map[string]interface{}) Term {
+	for name := range params {
+		if !containsString(q.params, name) {
+			return Term{lastErr: RQLDriverError{rqlError(fmt.Sprintf("Unknown query parameter %q", name))}}
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	for i, segment := range q.segments {
+		buf.Write(segment)
+		if i == len(q.params) {
+			break
+		}
+
+		v, ok := params[q.params[i]]
+		if !ok {
+			return Term{lastErr: RQLDriverError{rqlError(fmt.Sprintf("Missing query parameter %q", q.params[i]))}}
+		}
+		b, err := Expr(v).MarshalQuery()
+		if err != nil {
+			return Term{lastErr: err}
+		}
+		buf.Write(b)
+	}
+
+	return RawQuery(buf.Bytes())
+}
+
+// replaceParams returns a copy of a built term with each parameter replaced by
+// the result of f.
+func replaceParams(v interface{}, f func(queryParam) interface{}) interface{} {
+	switch v := v.(type) {
+	case queryParam:
+		return f(v)
+	case []interface{}:
+		res := make([]interface{}, len(v))
+		for i, x := range v {
+			res[i] = replaceParams(x, f)
+		}
+		return res
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(v))
+		for k, x := range v {
+			res[k] = replaceParams(x, f)
+		}
+		return res
+	default:
+		return v
+	}
+}
+
+// UnmarshalQuery parses the JSON encoding of a term, as returned by
+// MarshalQuery or produced by another RethinkDB driver, into a Term. Unlike
+// RawQuery the result is a regular term which can be extended with other terms
+// or used as an argument, for example:
+//
+//     t, err := r.UnmarshalQuery([]byte(`[15,["users"]]`))
+//     if err != nil {
+//         return err
+//     }
+//     t.Get("alice").Run(session)
+//
+// The JSON should only contain the term, not the query type or any options.
+// Function variables are renumbered so the term can safely be combined with
+// functions created by this driver.
+func UnmarshalQuery(b []byte) (Term, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return Term{}, RQLDriverError{rqlError(fmt.Sprintf("Error parsing query: %s", err.Error()))}
+	}
+
+	return parseTerm(v, map[int64]int64{})
+}
+
+// String returns a string representation of the query tree using the same
+// syntax as the Go driver, for example:
+//
+//     r.Table("users").Filter(func(var_1 r.Term) r.Term { return var_1.Field("age").Gt(30) })
+//
+// Optional arguments are printed as name=value pairs sorted by name. The
+// representation is intended for logs and error messages and cannot be parsed.
 func (t Term) String() string {
 	if t.isMockAnything {
 		return "r.MockAnything()"
@@ -215,6 +389,10 @@ func (t Term) String() string {
 		return "r.Row"
 	case p.Term_DATUM:
 		switch v := t.data.(type) {
+		case nil:
+			return "nil"
+		case queryParam:
+			return fmt.Sprintf("r.Param(%q)", string(v))
 		case string:
 			return strconv.Quote(v)
 		default:
@@ -222,8 +400,11 @@ func (t Term) String() string {
 		}
 	case p.Term_BINARY:
 		if len(t.args) == 0 {
-			return fmt.Sprintf("r.binary(<data>)")
+			return "r.Binary(<data>)"
 		}
+	case p.Term_MINVAL, p.Term_MAXVAL:
+		// MinVal and MaxVal are variables rather than functions
+		return "r." + t.name
 	}
 
 	if t.rootTerm {
@@ -244,12 +425,24 @@ type OptArgs interface {
 	toMap() map[string]interface{}
 }
 
+// OptArgs replaces the optional arguments of the term. args should be one of
+// the Opts structs, such as FilterOpts, or a map. The names of optional
+// arguments passed in a map are checked against the arguments accepted by the
+// term, unknown names cause an error when the query is run.
 func (t Term) OptArgs(args interface{}) Term {
 	switch args := args.(type) {
+	case nil:
 	case OptArgs:
 		t.optArgs = convertTermObj(args.toMap())
 	case map[string]interface{}:
 		t.optArgs = convertTermObj(args)
+		if t.lastErr == nil {
+			t.lastErr = t.validateOptArgNames()
+		}
+	default:
+		if t.lastErr == nil {
+			t.lastErr = RQLDriverError{rqlError(fmt.Sprintf("Unsupported optional arguments type %T, expected an Opts struct or map", args))}
+		}
 	}
 
 	return t
@@ -293,12 +486,64 @@ type ChangeResponse struct {
 	OldValue interface{} `gorethink:"old_val,omitempty"`
 	State    string      `gorethink:"state,omitempty"`
 	Error    string      `gorethink:"error,omitempty"`
+	// Type is only set when ChangesOpts.IncludeTypes is set.
+	Type string `gorethink:"type,omitempty"`
+	// NewOffset and OldOffset are only set when ChangesOpts.IncludeOffsets is
+	// set, they are nil if the document was added or removed.
+	NewOffset *int `gorethink:"new_offset,omitempty"`
+	OldOffset *int `gorethink:"old_offset,omitempty"`
 }
 
+// Types of change which can be returned in ChangeResponse.Type.
+const (
+	ChangeTypeAdd       = "add"
+	ChangeTypeRemove    = "remove"
+	ChangeTypeChange    = "change"
+	ChangeTypeInitial   = "initial"
+	ChangeTypeUninitial = "uninitial"
+	ChangeTypeState     = "state"
+)
+
+// Read modes which can be used with RunOpts.ReadMode, TableOpts.ReadMode and
+// ConnectOpts.ReadMode.
+const (
+	// ReadModeSingle returns values in memory (but not necessarily written to
+	// disk) on the primary replica, this is the default.
+	ReadModeSingle = "single"
+	// ReadModeMajority only returns values which are safely committed on disk
+	// on a majority of replicas.
+	ReadModeMajority = "majority"
+	// ReadModeOutdated returns values in memory from an arbitrary replica.
+	// When the session is connected to multiple hosts and no HostSelector is
+	// set these queries prefer the host with the lowest latency, the replica
+	// which is read is still chosen by the server.
+	ReadModeOutdated = "outdated"
+)
+
+// Bounds which can be used with the LeftBound and RightBound optional
+// arguments of the Between, During and Slice terms.
+const (
+	// BoundOpen excludes the bound from the range.
+	BoundOpen = "open"
+	// BoundClosed includes the bound in the range.
+	BoundClosed = "closed"
+)
+
 // RunOpts contains the optional arguments for the Run function.
+//
+// GroupFormat controls how grouped data is returned, "native" and "slice"
+// return a slice of group and reduction pairs (see GroupedResult) in the order
+// returned by the server, "map" returns a map from group to reduction and "raw"
+// returns the GROUPED_DATA pseudo-type.
+//
+// NumberFormat controls how numbers are decoded when reading results into
+// interface{} values, "native" decodes all numbers as float64, "json" as
+// json.Number and "int64" decodes integral numbers as int64 and other numbers
+// as float64. Large integers such as IDs and counters can lose precision when
+// decoded as float64. The default is set by ConnectOpts.NumberFormat.
 type RunOpts struct {
-	DB             interface{} `gorethink:"db,omitempty"`
-	Db             interface{} `gorethink:"db,omitempty"` // Deprecated
+	DB             interface{} `gorethink:"-"`
+	Db             interface{} `gorethink:"-"` // Deprecated
 	Profile        interface{} `gorethink:"profile,omitempty"`
 	Durability     interface{} `gorethink:"durability,omitempty"`
 	UseOutdated    interface{} `gorethink:"use_outdated,omitempty"` // Deprecated
@@ -307,6 +552,7 @@ type RunOpts struct {
 	GroupFormat    interface{} `gorethink:"group_format,omitempty"`
 	BinaryFormat   interface{} `gorethink:"binary_format,omitempty"`
 	GeometryFormat interface{} `gorethink:"geometry_format,omitempty"`
+	NumberFormat   interface{} `gorethink:"number_format,omitempty"`
 	ReadMode       interface{} `gorethink:"read_mode,omitempty"`
 
 	MinBatchRows              interface{} `gorethink:"min_batch_rows,omitempty"`
@@ -316,10 +562,22 @@ type RunOpts struct {
 	FirstBatchScaledownFactor interface{} `gorethink:"first_batch_scaledown_factor,omitempty"`
 
 	Context context.Context `gorethink:"-"`
+	// Timeout is the maximum amount of time the query may run for, including
+	// fetching further results using the returned cursor. If the timeout is
+	// exceeded then the query is stopped and ErrQueryTimeout is returned.
+	Timeout time.Duration `gorethink:"-"`
+	// RetryPolicy overrides ConnectOpts.RetryPolicy for this query.
+	RetryPolicy *RetryPolicy `gorethink:"-"`
+	// PrefetchBatches overrides ConnectOpts.PrefetchBatches for the cursor
+	// returned by this query.
+	PrefetchBatches int `gorethink:"-"`
+	// DisablePrefetch disables fetching batches in the background for the
+	// cursor returned by this query.
+	DisablePrefetch bool `gorethink:"-"`
 }
 
 func (o RunOpts) toMap() map[string]interface{} {
-	return optArgsToMap(o)
+	return withDBOptArg(optArgsToMap(o), o.DB, o.Db)
 }
 
 // Run runs a query using the given connection.
@@ -336,9 +594,17 @@ func (o RunOpts) toMap() map[string]interface{} {
 func (t Term) Run(s QueryExecutor, optArgs ...RunOpts) (*Cursor, error) {
 	opts := map[string]interface{}{}
 	var ctx context.Context = nil // if it's nil connection will form context from connection opts
+	var timeout time.Duration
+	var retryPolicy *RetryPolicy
+	var prefetchBatches int
+	var disablePrefetch bool
 	if len(optArgs) >= 1 {
 		opts = optArgs[0].toMap()
 		ctx = optArgs[0].Context
+		timeout = optArgs[0].Timeout
+		retryPolicy = optArgs[0].RetryPolicy
+		prefetchBatches = optArgs[0].PrefetchBatches
+		disablePrefetch = optArgs[0].DisablePrefetch
 	}
 
 	if s == nil || !s.IsConnected() {
@@ -349,8 +615,34 @@ func (t Term) Run(s QueryExecutor, optArgs ...RunOpts) (*Cursor, error) {
 	if err != nil {
 		return nil, err
 	}
+	q.retryPolicy = retryPolicy
+	q.prefetchBatches = prefetchBatches
+	q.disablePrefetch = disablePrefetch
 
-	return s.Query(ctx, q)
+	if timeout <= 0 {
+		return s.Query(ctx, q)
+	}
+
+	ctx, cancel := contextWithTimeout(ctx, timeout)
+	cursor, err := s.Query(ctx, q)
+	if cursor == nil {
+		cancel()
+	} else {
+		// The timeout applies until the cursor is closed
+		cursor.cancel = cancel
+	}
+
+	return cursor, err
+}
+
+// contextWithTimeout returns a copy of ctx which is cancelled after timeout, if
+// ctx is nil then the background context is used.
+func contextWithTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	return context.WithTimeout(ctx, timeout)
 }
 
 // RunWrite runs a query using the given connection but unlike Run automatically
@@ -411,8 +703,8 @@ func (t Term) ReadAll(dest interface{}, s QueryExecutor, optArgs ...RunOpts) err
 // When NoReply is true it causes the driver not to wait to receive the result
 // and return immediately.
 type ExecOpts struct {
-	DB             interface{} `gorethink:"db,omitempty"`
-	Db             interface{} `gorethink:"db,omitempty"` // Deprecated
+	DB             interface{} `gorethink:"-"`
+	Db             interface{} `gorethink:"-"` // Deprecated
 	Profile        interface{} `gorethink:"profile,omitempty"`
 	Durability     interface{} `gorethink:"durability,omitempty"`
 	UseOutdated    interface{} `gorethink:"use_outdated,omitempty"` // Deprecated
@@ -431,10 +723,16 @@ type ExecOpts struct {
 	NoReply interface{} `gorethink:"noreply,omitempty"`
 
 	Context context.Context `gorethink:"-"`
+	// Timeout is the maximum amount of time to wait for the query to complete,
+	// if the timeout is exceeded then the query is stopped and ErrQueryTimeout
+	// is returned.
+	Timeout time.Duration `gorethink:"-"`
+	// RetryPolicy overrides ConnectOpts.RetryPolicy for this query.
+	RetryPolicy *RetryPolicy `gorethink:"-"`
 }
 
 func (o ExecOpts) toMap() map[string]interface{} {
-	return optArgsToMap(o)
+	return withDBOptArg(optArgsToMap(o), o.DB, o.Db)
 }
 
 // Exec runs the query but does not return the result. Exec will still wait for
@@ -446,9 +744,13 @@ func (o ExecOpts) toMap() map[string]interface{} {
 func (t Term) Exec(s QueryExecutor, optArgs ...ExecOpts) error {
 	opts := map[string]interface{}{}
 	var ctx context.Context = nil // if it's nil connection will form context from connection opts
+	var timeout time.Duration
+	var retryPolicy *RetryPolicy
 	if len(optArgs) >= 1 {
 		opts = optArgs[0].toMap()
 		ctx = optArgs[0].Context
+		timeout = optArgs[0].Timeout
+		retryPolicy = optArgs[0].RetryPolicy
 	}
 
 	if s == nil || !s.IsConnected() {
@@ -459,6 +761,13 @@ func (t Term) Exec(s QueryExecutor, optArgs ...ExecOpts) error {
 	if err != nil {
 		return err
 	}
+	q.retryPolicy = retryPolicy
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = contextWithTimeout(ctx, timeout)
+		defer cancel()
+	}
 
 	return s.Exec(ctx, q)
 }
@@ -4,14 +4,112 @@ import (
 	p "gopkg.in/gorethink/gorethink.v3/ql2"
 )
 
+// DBConfig is the configuration of a database returned by the Config term.
+type DBConfig struct {
+	ID   string `gorethink:"id"`
+	Name string `gorethink:"name"`
+}
+
+// TableConfig is the configuration of a table returned by the Config term.
+type TableConfig struct {
+	ID         string             `gorethink:"id"`
+	Name       string             `gorethink:"name"`
+	DB         string             `gorethink:"db"`
+	PrimaryKey string             `gorethink:"primary_key"`
+	Shards     []TableShardConfig `gorethink:"shards"`
+	Indexes    []string           `gorethink:"indexes"`
+	WriteAcks  string             `gorethink:"write_acks"`
+	Durability string             `gorethink:"durability"`
+}
+
+// TableShardConfig is the configuration of a single shard of a table.
+type TableShardConfig struct {
+	PrimaryReplica    string   `gorethink:"primary_replica"`
+	Replicas          []string `gorethink:"replicas"`
+	NonVotingReplicas []string `gorethink:"nonvoting_replicas"`
+}
+
+// TableStatus is the status of a table returned by the Status term.
+type TableStatus struct {
+	ID     string             `gorethink:"id"`
+	Name   string             `gorethink:"name"`
+	DB     string             `gorethink:"db"`
+	Status TableReadiness     `gorethink:"status"`
+	Shards []TableShardStatus `gorethink:"shards"`
+	// RaftLeader is only returned when the table is not available.
+	RaftLeader string `gorethink:"raft_leader"`
+}
+
+// TableReadiness describes which operations a table is ready for.
+type TableReadiness struct {
+	AllReplicasReady      bool `gorethink:"all_replicas_ready"`
+	ReadyForOutdatedReads bool `gorethink:"ready_for_outdated_reads"`
+	ReadyForReads         bool `gorethink:"ready_for_reads"`
+	ReadyForWrites        bool `gorethink:"ready_for_writes"`
+}
+
+// TableShardStatus is the status of a single shard of a table.
+type TableShardStatus struct {
+	PrimaryReplicas []string             `gorethink:"primary_replicas"`
+	Replicas        []TableReplicaStatus `gorethink:"replicas"`
+}
+
+// TableReplicaStatus is the status of a single replica of a shard.
+type TableReplicaStatus struct {
+	Server string `gorethink:"server"`
+	State  string `gorethink:"state"`
+}
+
+// TableConfigChange contains the configuration of a table before and after it
+// was reconfigured.
+type TableConfigChange struct {
+	OldValue *TableConfig `gorethink:"old_val"`
+	NewValue *TableConfig `gorethink:"new_val"`
+}
+
+// TableStatusChange contains the status of a table before and after it was
+// reconfigured or rebalanced.
+type TableStatusChange struct {
+	OldValue *TableStatus `gorethink:"old_val"`
+	NewValue *TableStatus `gorethink:"new_val"`
+}
+
+// ReconfigureResponse is the result of the Reconfigure term.
+type ReconfigureResponse struct {
+	Reconfigured  int                 `gorethink:"reconfigured"`
+	ConfigChanges []TableConfigChange `gorethink:"config_changes"`
+	StatusChanges []TableStatusChange `gorethink:"status_changes"`
+}
+
+// RebalanceResponse is the result of the Rebalance term.
+type RebalanceResponse struct {
+	Rebalanced    int                 `gorethink:"rebalanced"`
+	StatusChanges []TableStatusChange `gorethink:"status_changes"`
+}
+
+// WaitResponse is the result of the Wait term.
+type WaitResponse struct {
+	Ready int `gorethink:"ready"`
+}
+
+// Table states which can be used with WaitOpts.WaitFor.
+const (
+	WaitForReadyForOutdatedReads = "ready_for_outdated_reads"
+	WaitForReadyForReads         = "ready_for_reads"
+	WaitForReadyForWrites        = "ready_for_writes"
+	WaitForAllReplicasReady      = "all_replicas_ready"
+)
+
 // Config can be used to read and/or update the configurations for individual
-// tables or databases.
+// tables or databases. The result can be decoded into a TableConfig or
+// DBConfig.
 func (t Term) Config() Term {
 	return constructMethodTerm(t, "Config", p.Term_CONFIG, []interface{}{}, map[string]interface{}{})
 }
 
 // Rebalance rebalances the shards of a table. When called on a database, all
-// the tables in that database will be rebalanced.
+// the tables in that database will be rebalanced. The result can be decoded
+// into a RebalanceResponse.
 func (t Term) Rebalance() Term {
 	return constructMethodTerm(t, "Rebalance", p.Term_REBALANCE, []interface{}{}, map[string]interface{}{})
 }
@@ -30,7 +128,8 @@ func (o ReconfigureOpts) toMap() map[string]interface{} {
 	return optArgsToMap(o)
 }
 
-// Reconfigure a table's sharding and replication.
+// Reconfigure a table's sharding and replication. The result can be decoded
+// into a ReconfigureResponse.
 func (t Term) Reconfigure(optArgs ...ReconfigureOpts) Term {
 	opts := map[string]interface{}{}
 	if len(optArgs) >= 1 {
@@ -39,7 +138,8 @@ func (t Term) Reconfigure(optArgs ...ReconfigureOpts) Term {
 	return constructMethodTerm(t, "Reconfigure", p.Term_RECONFIGURE, []interface{}{}, opts)
 }
 
-// Status return the status of a table
+// Status returns the status of a table, the result can be decoded into a
+// TableStatus.
 func (t Term) Status() Term {
 	return constructMethodTerm(t, "Status", p.Term_STATUS, []interface{}{}, map[string]interface{}{})
 }
@@ -70,6 +170,7 @@ func Wait(optArgs ...WaitOpts) Term {
 // Wait for a table or all the tables in a database to be ready. A table may be
 // temporarily unavailable after creation, rebalancing or reconfiguring. The
 // wait command blocks until the given table (or database) is fully up to date.
+// The result can be decoded into a WaitResponse.
 func (t Term) Wait(optArgs ...WaitOpts) Term {
 	opts := map[string]interface{}{}
 	if len(optArgs) >= 1 {
@@ -78,8 +179,30 @@ func (t Term) Wait(optArgs ...WaitOpts) Term {
 	return constructMethodTerm(t, "Wait", p.Term_WAIT, []interface{}{}, opts)
 }
 
-// Grant modifies access permissions for a user account, globally or on a
-// per-database or per-table basis.
+// GrantPermissions contains the permissions which can be granted to a user
+// account by the Grant term. Permissions which are not set are not changed,
+// setting a permission to false explicitly denies it.
+type GrantPermissions struct {
+	// Read allows reading data from tables.
+	Read interface{} `gorethink:"read,omitempty"`
+	// Write allows modifying data, including inserting, replacing and deleting.
+	Write interface{} `gorethink:"write,omitempty"`
+	// Config allows creating, reconfiguring and dropping tables and indexes.
+	Config interface{} `gorethink:"config,omitempty"`
+	// Connect allows using the http term, it can only be granted globally.
+	Connect interface{} `gorethink:"connect,omitempty"`
+}
+
+// Grant modifies access permissions for a user account globally. The first
+// argument is the name of the user and the second is the permissions,
+// usually a GrantPermissions value.
+func Grant(args ...interface{}) Term {
+	return constructRootTerm("Grant", p.Term_GRANT, args, map[string]interface{}{})
+}
+
+// Grant modifies access permissions for a user account on a per-database or
+// per-table basis. The first argument is the name of the user and the second
+// is the permissions, usually a GrantPermissions value.
 func (t Term) Grant(args ...interface{}) Term {
 	return constructMethodTerm(t, "Grant", p.Term_GRANT, args, map[string]interface{}{})
 }
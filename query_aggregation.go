@@ -55,6 +55,23 @@ func (o GroupOpts) toMap() map[string]interface{} {
 	return optArgsToMap(o)
 }
 
+// GroupedResult represents a single group returned by a grouped query, the
+// Group field contains the value the documents were grouped by and Reduction
+// contains either the documents in the group or the result of any reduction
+// chained after Group (such as Count, Sum or Avg).
+//
+// If the types of the group and reduction are known then a struct with the
+// same gorethink tags can be used instead to decode them directly, for example:
+//
+//     var res []struct {
+//         Group     string `gorethink:"group"`
+//         Reduction int    `gorethink:"reduction"`
+//     }
+type GroupedResult struct {
+	Group     interface{} `gorethink:"group"`
+	Reduction interface{} `gorethink:"reduction"`
+}
+
 // Group takes a stream and partitions it into multiple groups based on the
 // fields or functions provided. Commands chained after group will be
 // called on each of these grouped sub-streams, producing grouped data.
@@ -103,6 +120,12 @@ func MultiGroupByIndex(index interface{}, fieldOrFunctions ...interface{}) Term
 // Group takes a stream and partitions it into multiple groups based on the
 // fields or functions provided. Commands chained after group will be
 // called on each of these grouped sub-streams, producing grouped data.
+//
+// For example to count the number of users in each country:
+//
+//     r.Table("users").Group("country").Count()
+//
+// The result of a grouped query can be decoded into a slice of GroupedResult.
 func (t Term) Group(fieldOrFunctions ...interface{}) Term {
 	return constructMethodTerm(t, "Group", p.Term_GROUP, funcWrapArgs(fieldOrFunctions), map[string]interface{}{})
 }
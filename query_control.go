@@ -1,8 +1,12 @@
 package gorethink
 
 import (
+	"bytes"
 	"encoding/base64"
 	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
 
 	"reflect"
 
@@ -167,7 +171,13 @@ func (o JSOpts) toMap() map[string]interface{} {
 }
 
 // JS creates a JavaScript expression which is evaluated by the database when
-// running the query.
+// running the query. The Timeout option is the number of seconds the
+// expression may run for before it is stopped, the default is 5 seconds.
+//
+// If the expression evaluates to a JavaScript function then it can be used
+// wherever a ReQL function is accepted, for example:
+//
+//     r.Table("users").Filter(r.JS("(function (user) { return user.age > 30; })"))
 func JS(jssrc interface{}, optArgs ...JSOpts) Term {
 	opts := map[string]interface{}{}
 	if len(optArgs) >= 1 {
@@ -181,12 +191,13 @@ type HTTPOpts struct {
 	// General Options
 	Timeout      interface{} `gorethink:"timeout,omitempty"`
 	Reattempts   interface{} `gorethink:"reattempts,omitempty"`
-	Redirects    interface{} `gorethink:"redirect,omitempty"`
+	Redirects    interface{} `gorethink:"redirects,omitempty"`
 	Verify       interface{} `gorethink:"verify,omitempty"`
-	ResultFormat interface{} `gorethink:"resul_format,omitempty"`
+	ResultFormat interface{} `gorethink:"result_format,omitempty"`
 
 	// Request Options
 	Method interface{} `gorethink:"method,omitempty"`
+	// Auth is usually a HTTPAuth value.
 	Auth   interface{} `gorethink:"auth,omitempty"`
 	Params interface{} `gorethink:"params,omitempty"`
 	Header interface{} `gorethink:"header,omitempty"`
@@ -201,6 +212,14 @@ func (o HTTPOpts) toMap() map[string]interface{} {
 	return optArgsToMap(o)
 }
 
+// HTTPAuth contains the credentials used by the HTTP term, Type is either
+// "basic" (the default) or "digest".
+type HTTPAuth struct {
+	Type interface{} `gorethink:"type,omitempty"`
+	User interface{} `gorethink:"user,omitempty"`
+	Pass interface{} `gorethink:"pass,omitempty"`
+}
+
 // HTTP retrieves data from the specified URL over HTTP. The return type depends
 // on the resultFormat option, which checks the Content-Type of the response by
 // default.
@@ -212,20 +231,41 @@ func HTTP(url interface{}, optArgs ...HTTPOpts) Term {
 	return constructRootTerm("Http", p.Term_HTTP, []interface{}{url}, opts)
 }
 
-// JSON parses a JSON string on the server.
+// JSON parses a JSON string on the server. The string can be a value or come
+// from a document, for example to parse a field containing a JSON payload:
+//
+//     r.Table("events").Map(func(event r.Term) interface{} {
+//         return r.JSON(event.Field("payload"))
+//     })
 func JSON(args ...interface{}) Term {
 	return constructRootTerm("Json", p.Term_JSON, args, map[string]interface{}{})
 }
 
 // Error throws a runtime error. If called with no arguments inside the second argument
 // to `default`, re-throw the current error.
+//
+// Error is typically used in a branch to enforce an invariant on the server,
+// the query is aborted and Run returns an RQLUserError with the message:
+//
+//     r.Table("accounts").Get(id).Do(func(account r.Term) r.Term {
+//         return r.Branch(
+//             account.Field("balance").Ge(amount),
+//             r.Table("accounts").Get(id).Update(map[string]interface{}{
+//                 "balance": account.Field("balance").Sub(amount),
+//             }),
+//             r.Error("insufficient funds"),
+//         )
+//     })
 func Error(args ...interface{}) Term {
 	return constructRootTerm("Error", p.Term_ERROR, args, map[string]interface{}{})
 }
 
-// Args is a special term usd to splice an array of arguments into another term.
-// This is useful when you want to call a varadic term such as GetAll with a set
-// of arguments provided at runtime.
+// Args is a special term used to splice an array of arguments into another term.
+// This is useful when you want to call a variadic term such as GetAll with a set
+// of arguments provided at runtime, for example:
+//
+//     ids := []string{"a", "b", "c"}
+//     r.Table("users").GetAll(r.Args(ids))
 func Args(args ...interface{}) Term {
 	return constructRootTerm("Args", p.Term_ARGS, args, map[string]interface{}{})
 }
@@ -233,7 +273,13 @@ func Args(args ...interface{}) Term {
 // Binary encapsulates binary data within a query.
 //
 // The type of data binary accepts depends on the client language. In Go, it
-// expects either a byte array/slice or a bytes.Buffer.
+// expects either a byte array/slice, a bytes.Buffer or an io.Reader which is
+// read until EOF when Binary is called.
+//
+// Binary is only needed to create binary data within a query, []byte values
+// passed to other terms or stored in structs are automatically encoded as
+// binary objects and binary objects in query results are decoded back into
+// []byte values.
 //
 // Only a limited subset of ReQL commands may be chained after binary:
 //  - coerceTo can coerce binary objects to string types
@@ -249,6 +295,18 @@ func Binary(data interface{}) Term {
 		return constructRootTerm("Binary", p.Term_BINARY, []interface{}{data}, map[string]interface{}{})
 	case []byte:
 		b = data
+	case *bytes.Buffer:
+		b = data.Bytes()
+	case bytes.Buffer:
+		b = data.Bytes()
+	case io.Reader:
+		var err error
+		b, err = ioutil.ReadAll(data)
+		if err != nil {
+			t := constructRootTerm("Binary", p.Term_BINARY, []interface{}{}, map[string]interface{}{})
+			t.lastErr = err
+			return t
+		}
 	default:
 		typ := reflect.TypeOf(data)
 		if typ.Kind() == reflect.Slice && typ.Elem().Kind() == reflect.Uint8 {
@@ -276,29 +334,65 @@ func binaryTerm(data string) Term {
 
 // Do evaluates the expr in the context of one or more value bindings. The type of
 // the result is the type of the value returned from expr.
+//
+// The term Do is called on is passed as the first argument of the function,
+// followed by any other arguments, for example:
+//
+//     r.Table("users").Get("alice").Do(r.Table("users").Get("bob"), func(alice, bob r.Term) interface{} {
+//         return alice.Field("age").Add(bob.Field("age"))
+//     })
 func (t Term) Do(args ...interface{}) Term {
-	newArgs := []interface{}{}
-	newArgs = append(newArgs, funcWrap(args[len(args)-1]))
-	newArgs = append(newArgs, t)
-	newArgs = append(newArgs, args[:len(args)-1]...)
-
-	return constructRootTerm("Do", p.Term_FUNCALL, newArgs, map[string]interface{}{})
+	return doTerm([]interface{}{t}, args)
 }
 
 // Do evaluates the expr in the context of one or more value bindings. The type of
 // the result is the type of the value returned from expr.
+//
+// Any number of values can be bound, the function passed as the last argument
+// must take one argument per value, for example:
+//
+//     r.Do(1, 2, 3, func(x, y, z r.Term) interface{} {
+//         return x.Add(y).Mul(z)
+//     })
 func Do(args ...interface{}) Term {
-	newArgs := []interface{}{}
-	newArgs = append(newArgs, funcWrap(args[len(args)-1]))
-	newArgs = append(newArgs, args[:len(args)-1]...)
+	return doTerm(nil, args)
+}
+
+// doTerm creates a FUNCALL term which calls the last argument with the values
+// followed by the other arguments, checking that the number of values matches
+// the number of arguments of Go functions.
+func doTerm(values []interface{}, args []interface{}) Term {
+	if len(args) == 0 {
+		term := constructRootTerm("Do", p.Term_FUNCALL, []interface{}{}, map[string]interface{}{})
+		term.lastErr = RQLDriverError{rqlError("Do requires a function or expression")}
+		return term
+	}
+
+	expr := args[len(args)-1]
+	values = append(values, args[:len(args)-1]...)
+
+	term := constructRootTerm("Do", p.Term_FUNCALL, append([]interface{}{funcWrap(expr)}, values...), map[string]interface{}{})
+	if err := checkFuncArg("Do", expr, len(values)); err != nil {
+		term.lastErr = err
+	}
 
-	return constructRootTerm("Do", p.Term_FUNCALL, newArgs, map[string]interface{}{})
+	return term
 }
 
 // Branch evaluates one of two control paths based on the value of an expression.
 // branch is effectively an if renamed due to language constraints.
 //
 // The type of the result is determined by the type of the branch that gets executed.
+//
+// Branch also accepts any number of test and value pairs followed by a final
+// else value, the value of the first test which is true is returned, for
+// example:
+//
+//     r.Branch(
+//         r.Row.Field("age").Lt(13), "child",
+//         r.Row.Field("age").Lt(20), "teenager",
+//         "adult",
+//     )
 func Branch(args ...interface{}) Term {
 	return constructRootTerm("Branch", p.Term_BRANCH, args, map[string]interface{}{})
 }
@@ -306,6 +400,9 @@ func Branch(args ...interface{}) Term {
 // Branch evaluates one of two control paths based on the value of an expression.
 // branch is effectively an if renamed due to language constraints.
 //
+// The term Branch is called on is used as the first test, it can be followed
+// by more test and value pairs before the final else value.
+//
 // The type of the result is determined by the type of the branch that gets executed.
 func (t Term) Branch(args ...interface{}) Term {
 	return constructMethodTerm(t, "Branch", p.Term_BRANCH, args, map[string]interface{}{})
@@ -325,6 +422,15 @@ func (t Term) ForEach(args ...interface{}) Term {
 
 // Range generates a stream of sequential integers in a specified range. It
 // accepts 0, 1, or 2 arguments, all of which should be numbers.
+//
+// With no arguments Range returns an infinite stream starting from 0, with
+// one argument the stream starts at 0 and ends before the argument and with
+// two arguments the stream starts at the first argument and ends before the
+// second. The stream is generated lazily by the server, for example:
+//
+//     r.Range(10).Map(func(i r.Term) interface{} {
+//         return map[string]interface{}{"id": i}
+//     })
 func Range(args ...interface{}) Term {
 	return constructRootTerm("Range", p.Term_RANGE, args, map[string]interface{}{})
 }
@@ -334,6 +440,15 @@ func Range(args ...interface{}) Term {
 // its first argument returns null, returns its second argument. (Alternatively,
 // the second argument may be a function which will be called with either the
 // text of the non-existence error or null.)
+//
+// Default is usually cheaper and simpler than guarding every field access with
+// HasFields, for example:
+//
+//     r.Table("posts").Map(func(post r.Term) interface{} {
+//         return post.Field("author").Field("name").Default("Anonymous")
+//     })
+//
+// Other errors, such as type errors, are not caught by Default.
 func (t Term) Default(args ...interface{}) Term {
 	return constructMethodTerm(t, "Default", p.Term_DEFAULT, args, map[string]interface{}{})
 }
@@ -369,15 +484,51 @@ func (t Term) Info(args ...interface{}) Term {
 // UUID returns a UUID (universally unique identifier), a string that can be used
 // as a unique ID. If a string is passed to uuid as an argument, the UUID will be
 // deterministic, derived from the string’s SHA-1 hash.
+//
+// UUIDs can be generated by the server when inserting documents, for example:
+//
+//     r.Table("users").Insert(map[string]interface{}{
+//         "id":    r.UUID(),
+//         "email": email,
+//     })
 func UUID(args ...interface{}) Term {
 	return constructRootTerm("UUID", p.Term_UUID, args, map[string]interface{}{})
 }
 
+// queryParam is the value of a Param term, it cannot be encoded until it has
+// been replaced by Bind.
+type queryParam string
+
+func (q queryParam) MarshalJSON() ([]byte, error) {
+	return nil, fmt.Errorf("unbound query parameter %q, use Prepare and Bind to set its value", string(q))
+}
+
+// Param creates a named placeholder in a query template. The template is
+// serialized once using Prepare and the value of each placeholder is set when
+// the query is executed using Bind, for example:
+//
+//     q, err := r.Table("users").GetAll(r.Param("name"), r.GetAllOpts{
+//         Index: "name",
+//     }).Limit(r.Param("limit")).Prepare()
+//
+//     q.Bind(map[string]interface{}{"name": "alice", "limit": 10}).Run(session)
+//
+// A query containing a Param cannot be run without first being prepared.
+func Param(name string) Term {
+	return Term{
+		name:     "Param",
+		rootTerm: true,
+		termType: p.Term_DATUM,
+		data:     queryParam(name),
+	}
+}
+
 // RawQuery creates a new query from a JSON string, this bypasses any encoding
 // done by GoRethink. The query should not contain the query type or any options
 // as this should be handled using the normal driver API.
 //
-// THis query will only work if this is the only term in the query.
+// THis query will only work if this is the only term in the query, use
+// UnmarshalQuery to create a term which can be combined with other terms.
 func RawQuery(q []byte) Term {
 	data := json.RawMessage(q)
 	return Term{
@@ -4,6 +4,26 @@ import (
 	p "gopkg.in/gorethink/gorethink.v3/ql2"
 )
 
+// Units of distance which can be used with the Unit optional argument of the
+// Circle, Distance and GetNearest terms.
+const (
+	UnitMeter        = "m"
+	UnitKilometer    = "km"
+	UnitMile         = "mi"
+	UnitNauticalMile = "nm"
+	UnitFoot         = "ft"
+)
+
+// Reference ellipsoids which can be used with the GeoSystem optional argument
+// of the Circle, Distance and GetNearest terms.
+const (
+	// GeoSystemWGS84 is the World Geodetic System ellipsoid, this is the
+	// default.
+	GeoSystemWGS84 = "WGS84"
+	// GeoSystemUnitSphere is a perfect sphere with a radius of one meter.
+	GeoSystemUnitSphere = "unit_sphere"
+)
+
 // CircleOpts contains the optional arguments for the Circle term.
 type CircleOpts struct {
 	NumVertices interface{} `gorethink:"num_vertices,omitempty"`
@@ -21,7 +21,11 @@ func (t Term) OuterJoin(args ...interface{}) Term {
 
 // EqJoinOpts contains the optional arguments for the EqJoin term.
 type EqJoinOpts struct {
-	Index   interface{} `gorethink:"index,omitempty"`
+	// Index is the name of the index in the right table to match against
+	// instead of the primary key.
+	Index interface{} `gorethink:"index,omitempty"`
+	// Ordered, when true, returns the results in the order of the left
+	// sequence.
 	Ordered interface{} `gorethink:"ordered,omitempty"`
 }
 
@@ -31,13 +35,26 @@ func (o EqJoinOpts) toMap() map[string]interface{} {
 
 // EqJoin is an efficient join that looks up elements in the right table by primary key.
 //
+// The left argument is either the name of a field of the left sequence or a
+// function of type `func (r.Term) interface{}` which returns the key to look
+// up, for example:
+//
+//     r.Table("posts").EqJoin(func(post r.Term) interface{} {
+//         return post.Field("author").Field("id")
+//     }, r.Table("users"), r.EqJoinOpts{Ordered: true})
+//
 // Optional arguments: "index" (string - name of the index to use in right table instead of the primary key)
+// and "ordered" (bool - whether to return the results in the order of the left sequence)
 func (t Term) EqJoin(left, right interface{}, optArgs ...EqJoinOpts) Term {
 	opts := map[string]interface{}{}
 	if len(optArgs) >= 1 {
 		opts = optArgs[0].toMap()
 	}
-	return constructMethodTerm(t, "EqJoin", p.Term_EQ_JOIN, []interface{}{funcWrap(left), right}, opts)
+	term := constructMethodTerm(t, "EqJoin", p.Term_EQ_JOIN, []interface{}{funcWrap(left), right}, opts)
+	if err := checkFuncArg("EqJoin", left, 1); err != nil {
+		term.lastErr = err
+	}
+	return term
 }
 
 // Zip is used to 'zip' up the result of a join by merging the 'right' fields into 'left'
@@ -12,7 +12,14 @@ import (
 var Row = constructRootTerm("Doc", p.Term_IMPLICIT_VAR, []interface{}{}, map[string]interface{}{})
 
 // Literal replaces an object in a field instead of merging it with an existing
-// object in a merge or update operation.
+// object in a merge or update operation. For example to replace the whole
+// address of a user, removing any fields not in the new address:
+//
+//     r.Table("users").Get(id).Update(map[string]interface{}{
+//         "address": r.Literal(map[string]interface{}{"city": "London"}),
+//     })
+//
+// Calling Literal with no arguments removes the field.
 func Literal(args ...interface{}) Term {
 	return constructRootTerm("Literal", p.Term_LITERAL, args, map[string]interface{}{})
 }
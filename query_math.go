@@ -5,9 +5,11 @@ import (
 )
 
 var (
-	// MinVal represents the smallest possible value RethinkDB can store
+	// MinVal represents the smallest possible value RethinkDB can store, it
+	// can be used as the lower key of Between to leave the range unbounded.
 	MinVal = constructRootTerm("MinVal", p.Term_MINVAL, []interface{}{}, map[string]interface{}{})
-	// MaxVal represents the largest possible value RethinkDB can store
+	// MaxVal represents the largest possible value RethinkDB can store, it
+	// can be used as the upper key of Between to leave the range unbounded.
 	MaxVal = constructRootTerm("MaxVal", p.Term_MAXVAL, []interface{}{}, map[string]interface{}{})
 )
 
@@ -176,9 +178,9 @@ func (o RandomOpts) toMap() map[string]interface{} {
 // which case x will be returned. Specifying a floating point number without the
 // float option will raise an error.
 //
-// Note: Any integer responses can be be coerced to floating-points, when
+// Note: Any integer responses can be coerced to floating-points, when
 // unmarshaling to a Go floating-point type. The last argument given will always
-// be the ‘open’ side of the range,  but when generating a floating-point
+// be the ‘open’ side of the range, but when generating a floating-point
 // number, the ‘open’ side may be less than the ‘closed’ side.
 func Random(args ...interface{}) Term {
 	var opts = map[string]interface{}{}
@@ -194,12 +196,14 @@ func Random(args ...interface{}) Term {
 	return constructRootTerm("Random", p.Term_RANDOM, args, opts)
 }
 
-// Round causes the input number to be rounded the given value to the nearest whole integer.
+// Round rounds the given value to the nearest whole integer, values exactly
+// halfway between two integers are rounded away from zero.
 func (t Term) Round(args ...interface{}) Term {
 	return constructMethodTerm(t, "Round", p.Term_ROUND, args, map[string]interface{}{})
 }
 
-// Round causes the input number to be rounded the given value to the nearest whole integer.
+// Round rounds the given value to the nearest whole integer, values exactly
+// halfway between two integers are rounded away from zero.
 func Round(args ...interface{}) Term {
 	return constructRootTerm("Round", p.Term_ROUND, args, map[string]interface{}{})
 }
@@ -227,3 +231,71 @@ func (t Term) Floor(args ...interface{}) Term {
 func Floor(args ...interface{}) Term {
 	return constructRootTerm("Floor", p.Term_FLOOR, args, map[string]interface{}{})
 }
+
+// BitAnd performs a bitwise AND of two or more integers.
+//
+// Bitwise operations are only supported by RethinkDB 2.4 or later.
+func (t Term) BitAnd(args ...interface{}) Term {
+	return constructMethodTerm(t, "BitAnd", p.Term_BIT_AND, args, map[string]interface{}{})
+}
+
+// BitAnd performs a bitwise AND of two or more integers.
+//
+// Bitwise operations are only supported by RethinkDB 2.4 or later.
+func BitAnd(args ...interface{}) Term {
+	return constructRootTerm("BitAnd", p.Term_BIT_AND, args, map[string]interface{}{})
+}
+
+// BitOr performs a bitwise OR of two or more integers.
+func (t Term) BitOr(args ...interface{}) Term {
+	return constructMethodTerm(t, "BitOr", p.Term_BIT_OR, args, map[string]interface{}{})
+}
+
+// BitOr performs a bitwise OR of two or more integers.
+func BitOr(args ...interface{}) Term {
+	return constructRootTerm("BitOr", p.Term_BIT_OR, args, map[string]interface{}{})
+}
+
+// BitXor performs a bitwise XOR of two or more integers.
+func (t Term) BitXor(args ...interface{}) Term {
+	return constructMethodTerm(t, "BitXor", p.Term_BIT_XOR, args, map[string]interface{}{})
+}
+
+// BitXor performs a bitwise XOR of two or more integers.
+func BitXor(args ...interface{}) Term {
+	return constructRootTerm("BitXor", p.Term_BIT_XOR, args, map[string]interface{}{})
+}
+
+// BitNot inverts every bit of an integer.
+func (t Term) BitNot() Term {
+	return constructMethodTerm(t, "BitNot", p.Term_BIT_NOT, []interface{}{}, map[string]interface{}{})
+}
+
+// BitNot inverts every bit of an integer.
+func BitNot(arg interface{}) Term {
+	return constructRootTerm("BitNot", p.Term_BIT_NOT, []interface{}{arg}, map[string]interface{}{})
+}
+
+// BitSal performs an arithmetic left shift of an integer by the given number
+// of bits.
+func (t Term) BitSal(args ...interface{}) Term {
+	return constructMethodTerm(t, "BitSal", p.Term_BIT_SAL, args, map[string]interface{}{})
+}
+
+// BitSal performs an arithmetic left shift of an integer by the given number
+// of bits.
+func BitSal(args ...interface{}) Term {
+	return constructRootTerm("BitSal", p.Term_BIT_SAL, args, map[string]interface{}{})
+}
+
+// BitSar performs an arithmetic right shift of an integer by the given number
+// of bits, preserving the sign.
+func (t Term) BitSar(args ...interface{}) Term {
+	return constructMethodTerm(t, "BitSar", p.Term_BIT_SAR, args, map[string]interface{}{})
+}
+
+// BitSar performs an arithmetic right shift of an integer by the given number
+// of bits, preserving the sign.
+func BitSar(args ...interface{}) Term {
+	return constructRootTerm("BitSar", p.Term_BIT_SAR, args, map[string]interface{}{})
+}
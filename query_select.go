@@ -56,6 +56,27 @@ func (t Term) Table(name interface{}, optArgs ...TableOpts) Term {
 	return constructMethodTerm(t, "Table", p.Term_TABLE, []interface{}{name}, opts)
 }
 
+// CompoundKey creates an array key from multiple values which can be used with
+// Get, GetAll and Between when a table uses a compound primary key or when
+// querying a compound index, for example:
+//
+//     r.Table("scores").Get(r.CompoundKey("player", 1))
+//
+// Structs can map the parts of a compound key to separate fields by including
+// the position of the part in the field tag, for example:
+//
+//     type Score struct {
+//         Player string `gorethink:"id[0]"`
+//         Level  int    `gorethink:"id[1]"`
+//     }
+func CompoundKey(parts ...interface{}) Term {
+	term := Expr(parts)
+	if len(parts) == 0 {
+		term.lastErr = RQLDriverError{rqlError("CompoundKey requires at least one part")}
+	}
+	return term
+}
+
 // Get gets a document by primary key. If nothing was found, RethinkDB will return a nil value.
 func (t Term) Get(args ...interface{}) Term {
 	return constructMethodTerm(t, "Get", p.Term_GET, args, map[string]interface{}{})
@@ -74,19 +95,36 @@ func (o GetAllOpts) toMap() map[string]interface{} {
 // index. Multiple values can be passed this function if you want to select multiple
 // documents. If the documents you are fetching have composite keys then each
 // argument should be a slice. For more information see the examples.
+//
+// A GetAllOpts value can be passed as the last argument to use a secondary
+// index instead of the primary index.
 func (t Term) GetAll(keys ...interface{}) Term {
-	return constructMethodTerm(t, "GetAll", p.Term_GET_ALL, keys, map[string]interface{}{})
+	var opts = map[string]interface{}{}
+
+	// Look for options map
+	if len(keys) > 0 {
+		if possibleOpts, ok := keys[len(keys)-1].(GetAllOpts); ok {
+			opts = possibleOpts.toMap()
+			keys = keys[:len(keys)-1]
+		}
+	}
+
+	return constructMethodTerm(t, "GetAll", p.Term_GET_ALL, keys, opts)
 }
 
 // GetAllByIndex gets all documents where the given value matches the value of
-// the requested index.
+// the requested index. When using a compound index each key should be a slice,
+// for example:
+//
+//     r.Table("users").GetAllByIndex("full_name", []string{"John", "Smith"})
 func (t Term) GetAllByIndex(index interface{}, keys ...interface{}) Term {
 	return constructMethodTerm(t, "GetAll", p.Term_GET_ALL, keys, map[string]interface{}{"index": index})
 }
 
 // BetweenOpts contains the optional arguments for the Between term
 type BetweenOpts struct {
-	Index      interface{} `gorethink:"index,omitempty"`
+	Index interface{} `gorethink:"index,omitempty"`
+	// LeftBound and RightBound should be BoundOpen or BoundClosed.
 	LeftBound  interface{} `gorethink:"left_bound,omitempty"`
 	RightBound interface{} `gorethink:"right_bound,omitempty"`
 }
@@ -95,24 +133,39 @@ func (o BetweenOpts) toMap() map[string]interface{} {
 	return optArgsToMap(o)
 }
 
+func (o BetweenOpts) validate() error {
+	if err := validateBound("left_bound", o.LeftBound); err != nil {
+		return err
+	}
+
+	return validateBound("right_bound", o.RightBound)
+}
+
 // Between gets all documents between two keys. Accepts three optional arguments:
 // index, leftBound, and rightBound. If index is set to the name of a secondary
 // index, between will return all documents where that index’s value is in the
 // specified range (it uses the primary key by default). leftBound or rightBound
-// may be set to open or closed to indicate whether or not to include that endpoint
-// of the range (by default, leftBound is closed and rightBound is open).
+// may be set to BoundOpen or BoundClosed to indicate whether or not to include
+// that endpoint of the range (by default, leftBound is closed and rightBound is
+// open). Any other string causes an error when the query is run.
 //
-// You may also use the special constants r.minval and r.maxval for boundaries,
+// You may also use the special terms MinVal and MaxVal for boundaries,
 // which represent “less than any index key” and “more than any index key”
-// respectively. For instance, if you use r.minval as the lower key, then between
+// respectively. For instance, if you use MinVal as the lower key, then between
 // will return all documents whose primary keys (or indexes) are less than the
 // specified upper key.
 func (t Term) Between(lowerKey, upperKey interface{}, optArgs ...BetweenOpts) Term {
 	opts := map[string]interface{}{}
+	var err error
 	if len(optArgs) >= 1 {
 		opts = optArgs[0].toMap()
+		err = optArgs[0].validate()
 	}
-	return constructMethodTerm(t, "Between", p.Term_BETWEEN, []interface{}{lowerKey, upperKey}, opts)
+
+	term := constructMethodTerm(t, "Between", p.Term_BETWEEN, []interface{}{lowerKey, upperKey}, opts)
+	term.lastErr = err
+
+	return term
 }
 
 // FilterOpts contains the optional arguments for the Filter term
@@ -137,5 +190,9 @@ func (t Term) Filter(f interface{}, optArgs ...FilterOpts) Term {
 	if len(optArgs) >= 1 {
 		opts = optArgs[0].toMap()
 	}
-	return constructMethodTerm(t, "Filter", p.Term_FILTER, []interface{}{funcWrap(f)}, opts)
+	term := constructMethodTerm(t, "Filter", p.Term_FILTER, []interface{}{funcWrap(f)}, opts)
+	if err := checkFuncArg("Filter", f, 1); err != nil {
+		term.lastErr = err
+	}
+	return term
 }
@@ -4,6 +4,29 @@ import (
 	p "gopkg.in/gorethink/gorethink.v3/ql2"
 )
 
+// MatchResult is the result of the Match term.
+type MatchResult struct {
+	// Str is the matched string.
+	Str string `gorethink:"str"`
+	// Start is the index of the start of the matched string.
+	Start int `gorethink:"start"`
+	// End is the index of the end of the matched string.
+	End int `gorethink:"end"`
+	// Groups contains the capture groups defined with parentheses, groups
+	// which did not match are nil.
+	Groups []*MatchGroup `gorethink:"groups"`
+}
+
+// MatchGroup is a capture group of a MatchResult.
+type MatchGroup struct {
+	// Str is the string matched by the group.
+	Str string `gorethink:"str"`
+	// Start is the index of the start of the matched string.
+	Start int `gorethink:"start"`
+	// End is the index of the end of the matched string.
+	End int `gorethink:"end"`
+}
+
 // Match matches against a regular expression. If no match is found, returns
 // null. If there is a match then an object with the following fields is
 // returned:
@@ -12,6 +35,9 @@ import (
 //   end: The matched string’s end
 //   groups: The capture groups defined with parentheses
 //
+// The result can be decoded into a MatchResult, or a *MatchResult if the
+// regular expression might not match.
+//
 // Accepts RE2 syntax (https://code.google.com/p/re2/wiki/Syntax). You can
 // enable case-insensitive matching by prefixing the regular expression with
 // (?i). See the linked RE2 documentation for more flags.
@@ -148,17 +148,55 @@ func (t Term) IndexWait(args ...interface{}) Term {
 	return constructMethodTerm(t, "IndexWait", p.Term_INDEX_WAIT, args, map[string]interface{}{})
 }
 
+// WriteHook is the write hook of a table returned by the GetWriteHook term.
+type WriteHook struct {
+	// Function is the serialized hook, it can be passed to SetWriteHook to
+	// install the same hook on another table.
+	Function []byte `gorethink:"function"`
+	// Query is a description of the hook in the JavaScript driver's syntax.
+	Query string `gorethink:"query"`
+}
+
+// SetWriteHook sets the function which is called whenever a document in the
+// table is written, replacing any existing hook. The hook is a function of
+// the form func(context, oldVal, newVal r.Term) interface{} which returns the
+// document to write, or raises an error to reject the write. The hook can
+// also be the Function of a WriteHook returned by GetWriteHook, or nil to
+// remove the hook.
+//
+// Write hooks are only supported by RethinkDB 2.4 or later.
+func (t Term) SetWriteHook(hook interface{}) Term {
+	return constructMethodTerm(t, "SetWriteHook", p.Term_SET_WRITE_HOOK, []interface{}{hook}, map[string]interface{}{})
+}
+
+// GetWriteHook returns the write hook of the table, or null if the table has
+// no hook. The result can be decoded into a *WriteHook.
+//
+// Write hooks are only supported by RethinkDB 2.4 or later.
+func (t Term) GetWriteHook() Term {
+	return constructMethodTerm(t, "GetWriteHook", p.Term_GET_WRITE_HOOK, []interface{}{}, map[string]interface{}{})
+}
+
 // ChangesOpts contains the optional arguments for the Changes term
 type ChangesOpts struct {
-	Squash              interface{} `gorethink:"squash,omitempty"`
-	IncludeInitial      interface{} `gorethink:"include_initial,omitempty"`
-	IncludeStates       interface{} `gorethink:"include_states,omitempty"`
-	IncludeOffsets      interface{} `gorethink:"include_offsets,omitempty"`
-	IncludeTypes        interface{} `gorethink:"include_types,omitempty"`
+	// Squash controls how changes are batched, either a bool or the number of
+	// seconds to wait while combining changes to the same document.
+	Squash interface{} `gorethink:"squash,omitempty"`
+	// IncludeInitial sends the initial values of the documents before any
+	// changes.
+	IncludeInitial interface{} `gorethink:"include_initial,omitempty"`
+	// IncludeStates sends state changes in ChangeResponse.State.
+	IncludeStates interface{} `gorethink:"include_states,omitempty"`
+	// IncludeOffsets sends the positions of changed documents when changes
+	// are made to an OrderBy.Limit query.
+	IncludeOffsets interface{} `gorethink:"include_offsets,omitempty"`
+	// IncludeTypes sends the type of each change in ChangeResponse.Type.
+	IncludeTypes interface{} `gorethink:"include_types,omitempty"`
+	// ChangefeedQueueSize is the number of changes the server buffers
+	// before returning an error, the default is 100,000.
 	ChangefeedQueueSize interface{} `gorethink:"changefeed_queue_size,omitempty"`
 }
 
-// ChangesOpts contains the optional arguments for the Changes term
 func (o ChangesOpts) toMap() map[string]interface{} {
 	return optArgsToMap(o)
 }
@@ -3,11 +3,17 @@ package gorethink
 import (
 	"bytes"
 	"encoding/json"
+	"reflect"
+	"regexp"
+	"strings"
 	"sync"
 	"testing"
+	"testing/iotest"
 	"time"
 
 	test "gopkg.in/check.v1"
+	"gopkg.in/gorethink/gorethink.v3/encoding"
+	p "gopkg.in/gorethink/gorethink.v3/ql2"
 )
 
 func (s *RethinkSuite) TestQueryRun(c *test.C) {
@@ -44,6 +50,58 @@ func (s *RethinkSuite) TestQueryExec(c *test.C) {
 	c.Assert(err, test.IsNil)
 }
 
+func (s *RethinkSuite) TestQueryRunTimeout(c *test.C) {
+	_, err := JS("while(true) {}", JSOpts{Timeout: 5}).Run(session, RunOpts{
+		Timeout: 100 * time.Millisecond,
+	})
+	c.Assert(err, test.Equals, ErrQueryTimeout)
+
+	var response string
+	res, err := Expr("Test").Run(session, RunOpts{
+		Timeout: time.Second,
+	})
+	c.Assert(err, test.IsNil)
+	err = res.One(&response)
+	c.Assert(err, test.IsNil)
+	c.Assert(response, test.Equals, "Test")
+}
+
+func (s *RethinkSuite) TestQueryExecTimeout(c *test.C) {
+	err := JS("while(true) {}", JSOpts{Timeout: 5}).Exec(session, ExecOpts{
+		Timeout: 100 * time.Millisecond,
+	})
+	c.Assert(err, test.Equals, ErrQueryTimeout)
+}
+
+func (s *RethinkSuite) TestQueryExecNoReplyWait(c *test.C) {
+	DB("test").TableDrop("noreply").Exec(session)
+	DB("test").TableCreate("noreply").Exec(session)
+	DB("test").Table("noreply").Wait().Exec(session)
+
+	// Send the queries concurrently so that multiple connections are used
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			err := DB("test").Table("noreply").Insert(map[string]interface{}{
+				"num": i,
+			}).Exec(session, ExecOpts{NoReply: true})
+			c.Assert(err, test.IsNil)
+		}(i)
+	}
+	wg.Wait()
+
+	err := session.NoReplyWait()
+	c.Assert(err, test.IsNil)
+
+	var count int
+	err = DB("test").Table("noreply").Count().ReadOne(&count, session)
+	c.Assert(err, test.IsNil)
+	c.Assert(count, test.Equals, 100)
+}
+
 func (s *RethinkSuite) TestQueryRunWrite(c *test.C) {
 	query := DB("test").Table("test").Insert([]interface{}{
 		map[string]interface{}{"num": 1},
@@ -262,6 +320,30 @@ func (s *RethinkSuite) TestControlJs(c *test.C) {
 	c.Assert(response, test.Equals, 1)
 }
 
+func (s *RethinkSuite) TestControlJsTimeout(c *test.C) {
+	err := JS("while (true) {}", JSOpts{Timeout: 0.5}).Exec(session)
+	c.Assert(err, test.NotNil)
+}
+
+func (s *RethinkSuite) TestControlJsFunc(c *test.C) {
+	var response []int
+	query := Expr([]int{1, 2, 3, 4}).Filter(JS("(function (n) { return n % 2 == 0; })"))
+	err := query.ReadAll(&response, session)
+	c.Assert(err, test.IsNil)
+	c.Assert(response, test.DeepEquals, []int{2, 4})
+}
+
+func (s *RethinkSuite) TestControlJSON(c *test.C) {
+	var response map[string]interface{}
+	doc := Expr(map[string]interface{}{"payload": `{"a": [1, 2], "b": "c"}`})
+	err := JSON(doc.Field("payload")).ReadOne(&response, session)
+	c.Assert(err, test.IsNil)
+	c.Assert(response, jsonEquals, map[string]interface{}{
+		"a": []interface{}{1, 2},
+		"b": "c",
+	})
+}
+
 func (s *RethinkSuite) TestControlHttp(c *test.C) {
 	if testing.Short() {
 		c.Skip("-short set")
@@ -280,6 +362,18 @@ func (s *RethinkSuite) TestControlHttp(c *test.C) {
 	})
 }
 
+func (s *RethinkSuite) TestControlHttpBuild(c *test.C) {
+	query := HTTP("httpbin.org/basic-auth/user/pass", HTTPOpts{
+		Redirects:    1,
+		ResultFormat: "json",
+		Auth:         HTTPAuth{User: "user", Pass: "pass"},
+	})
+
+	b, err := query.MarshalQuery()
+	c.Assert(err, test.IsNil)
+	c.Assert(string(b), test.Equals, `[153,["httpbin.org/basic-auth/user/pass"],{"auth":{"pass":"pass","user":"user"},"redirects":1,"result_format":"json"}]`)
+}
+
 func (s *RethinkSuite) TestControlError(c *test.C) {
 	query := Error("An error occurred")
 	err := query.Exec(session)
@@ -291,6 +385,129 @@ func (s *RethinkSuite) TestControlError(c *test.C) {
 	c.Assert(err.Error(), test.Equals, "gorethink: An error occurred in:\nr.Error(\"An error occurred\")")
 }
 
+func (s *RethinkSuite) TestControlErrorBranch(c *test.C) {
+	query := Expr(5).Do(func(n Term) Term {
+		return Branch(n.Gt(10), n, Error("n is too small"))
+	})
+	err := query.Exec(session)
+	c.Assert(err, test.FitsTypeOf, RQLUserError{})
+	c.Assert(err.Error(), test.Matches, "gorethink: n is too small in:\n.*")
+
+	// Error with no arguments re-throws the error caught by Default
+	query = Expr(map[string]interface{}{}).Field("a").Default(func(msg Term) Term {
+		return Error()
+	})
+	err = query.Exec(session)
+	c.Assert(err, test.NotNil)
+}
+
+func (s *RethinkSuite) TestControlDefault(c *test.C) {
+	var response interface{}
+
+	// Missing fields are replaced
+	res, err := Expr(map[string]interface{}{"a": 1}).Field("b").Default(2).Run(session)
+	c.Assert(err, test.IsNil)
+	err = res.One(&response)
+	c.Assert(err, test.IsNil)
+	c.Assert(response, jsonEquals, 2)
+
+	// Nested missing fields are replaced
+	res, err = Expr(map[string]interface{}{"a": 1}).Field("b").Field("c").Default("d").Run(session)
+	c.Assert(err, test.IsNil)
+	err = res.One(&response)
+	c.Assert(err, test.IsNil)
+	c.Assert(response, jsonEquals, "d")
+
+	// Null values are replaced
+	res, err = Expr(map[string]interface{}{"a": nil}).Field("a").Default(3).Run(session)
+	c.Assert(err, test.IsNil)
+	err = res.One(&response)
+	c.Assert(err, test.IsNil)
+	c.Assert(response, jsonEquals, 3)
+
+	// A function is called with the error message
+	res, err = Expr(map[string]interface{}{"a": 1}).Field("b").Default(func(msg Term) Term {
+		return msg.Match("No attribute")
+	}).Ne(nil).Run(session)
+	c.Assert(err, test.IsNil)
+	err = res.One(&response)
+	c.Assert(err, test.IsNil)
+	c.Assert(response, jsonEquals, true)
+
+	// Other errors are not caught
+	_, err = Expr(1).Add("a").Default(4).Run(session)
+	c.Assert(err, test.NotNil)
+}
+
+func (s *RethinkSuite) TestControlDoMultipleBuild(c *test.C) {
+	terms := []Term{
+		Do(1, 2, 3, func(x, y, z Term) Term { return x.Add(y).Mul(z) }),
+		Expr(1).Do(2, 3, func(x, y, z Term) Term { return x.Add(y).Mul(z) }),
+	}
+
+	for _, term := range terms {
+		b, err := term.MarshalQuery()
+		c.Assert(err, test.IsNil)
+		c.Assert(string(b), test.Matches, `\[64,\[\[69,\[\[2,\[\d+,\d+,\d+\]\],\[26,.*\]\]\],1,2,3\]\]`)
+	}
+
+	// The function must take one argument per value
+	_, err := Do(1, 2, func(x Term) Term { return x }).Build()
+	c.Assert(err, test.ErrorMatches, "gorethink: Do function takes 1 argument but was given 2")
+
+	_, err = Expr(1).Do(func(x, y Term) Term { return x }).Build()
+	c.Assert(err, test.ErrorMatches, "gorethink: Do function takes 2 arguments but was given 1")
+
+	_, err = Do().Build()
+	c.Assert(err, test.NotNil)
+}
+
+func (s *RethinkSuite) TestTermFuncArgs(c *test.C) {
+	valid := []Term{
+		Range(3).Map(func() interface{} { return 0 }),
+		Expr([]int{1}).Filter(func(row Term) interface{} { return row.Gt(0) }),
+		Table("users").Update(func(row interface{}) interface{} { return map[string]interface{}{} }),
+		Map([]int{1}, []int{2}, []int{3}, []int{4}, func(a, b, c, d Term) interface{} { return a }),
+	}
+	for _, term := range valid {
+		_, err := term.Build()
+		c.Assert(err, test.IsNil)
+	}
+
+	invalid := map[string]Term{
+		"gorethink: Map function takes 2 arguments but was given 1":                           Expr([]int{1}).Map(func(a, b Term) interface{} { return a }),
+		"gorethink: Map function takes 1 argument but was given 2":                            Map([]int{1}, []int{2}, func(a Term) interface{} { return a }),
+		"gorethink: Filter function takes 2 arguments but was given 1":                        Expr([]int{1}).Filter(func(a, b Term) interface{} { return a }),
+		"gorethink: Replace function must not be variadic":                                    Table("users").Replace(func(rows ...Term) interface{} { return nil }),
+		"gorethink: Update function argument 2 is of type int, expected Term or interface {}": Table("users").Update(func(row Term, i int) interface{} { return row }),
+		"gorethink: Update function must have a single return value, has 2":                   Table("users").Update(func(row Term) (interface{}, error) { return row, nil }),
+		"gorethink: Function argument 1 is of type string, expected Term or interface {}":     Expr(func(s string) interface{} { return s }),
+	}
+	for expected, term := range invalid {
+		_, err := term.Build()
+		c.Assert(err, test.NotNil)
+		c.Assert(err.Error(), test.Equals, expected)
+	}
+}
+
+func (s *RethinkSuite) TestControlBranchMultiple(c *test.C) {
+	classify := func(age int) string {
+		var response string
+		res, err := Expr(age).Do(func(age Term) Term {
+			return Branch(age.Lt(13), "child", age.Lt(20), "teenager", "adult")
+		}).Run(session)
+		c.Assert(err, test.IsNil)
+
+		err = res.One(&response)
+		c.Assert(err, test.IsNil)
+		return response
+	}
+
+	c.Assert(classify(10), test.Equals, "child")
+	c.Assert(classify(15), test.Equals, "teenager")
+	c.Assert(classify(40), test.Equals, "adult")
+}
+
 func (s *RethinkSuite) TestControlDoNothing(c *test.C) {
 	var response []interface{}
 	query := Do([]interface{}{map[string]interface{}{"a": 1}, map[string]interface{}{"a": 2}, map[string]interface{}{"a": 3}})
@@ -314,6 +531,16 @@ func (s *RethinkSuite) TestControlArgs(c *test.C) {
 	c.Assert(response.Unix(), test.Equals, int64(1405123200))
 }
 
+func (s *RethinkSuite) TestControlArgsSlice(c *test.C) {
+	fields := []string{"a", "c"}
+
+	var response map[string]interface{}
+	query := Expr(map[string]interface{}{"a": 1, "b": 2, "c": 3}).Pluck(Args(fields))
+	err := query.ReadOne(&response, session)
+	c.Assert(err, test.IsNil)
+	c.Assert(response, test.DeepEquals, map[string]interface{}{"a": float64(1), "c": float64(3)})
+}
+
 func (s *RethinkSuite) TestControlBinaryByteArray(c *test.C) {
 	var response []byte
 
@@ -352,6 +579,24 @@ func (s *RethinkSuite) TestControlBinaryByteSlice(c *test.C) {
 	c.Assert(response, test.Equals, [5]byte{'h', 'e', 'l', 'l', 'o'})
 }
 
+func (s *RethinkSuite) TestControlBinaryBuffer(c *test.C) {
+	terms := []Term{
+		Binary(bytes.NewBufferString("Hello World")),
+		Binary(*bytes.NewBufferString("Hello World")),
+		Binary(strings.NewReader("Hello World")),
+		Expr([]byte("Hello World")),
+	}
+
+	for _, term := range terms {
+		b, err := term.MarshalQuery()
+		c.Assert(err, test.IsNil)
+		c.Assert(string(b), test.Equals, `{"$reql_type$":"BINARY","data":"SGVsbG8gV29ybGQ="}`)
+	}
+
+	_, err := Binary(iotest.TimeoutReader(strings.NewReader("Hello World"))).Build()
+	c.Assert(err, test.Equals, iotest.ErrTimeout)
+}
+
 func (s *RethinkSuite) TestControlBinaryExpr(c *test.C) {
 	var response []byte
 
@@ -594,6 +839,33 @@ func (s *RethinkSuite) TestTableChangesIncludeInitial(c *test.C) {
 	c.Assert(n, test.Equals, 10)
 }
 
+func (s *RethinkSuite) TestTableChangesIncludeTypes(c *test.C) {
+	DB("test").TableDrop("changes").Exec(session)
+	DB("test").TableCreate("changes").Exec(session)
+	DB("test").Table("changes").Wait().Exec(session)
+
+	DB("test").Table("changes").Insert(map[string]interface{}{"id": "a", "n": 1}).Exec(session)
+
+	res, err := DB("test").Table("changes").Get("a").Changes(ChangesOpts{
+		IncludeInitial: true,
+		IncludeTypes:   true,
+	}).Run(session)
+	c.Assert(err, test.IsNil)
+	defer res.Close()
+
+	var change ChangeResponse
+	c.Assert(res.Next(&change), test.Equals, true)
+	c.Assert(change.Type, test.Equals, ChangeTypeInitial)
+
+	DB("test").Table("changes").Get("a").Update(map[string]interface{}{"n": 2}).Exec(session)
+
+	change = ChangeResponse{}
+	c.Assert(res.Next(&change), test.Equals, true)
+	c.Assert(change.Type, test.Equals, ChangeTypeChange)
+	c.Assert(change.OldValue, test.NotNil)
+	c.Assert(change.NewValue, test.NotNil)
+}
+
 func (s *RethinkSuite) TestWriteReference(c *test.C) {
 	author := Author{
 		ID:   "1",
@@ -649,6 +921,115 @@ func (s *RethinkSuite) TestWriteConflict(c *test.C) {
 	c.Assert(IsConflictErr(err), test.Equals, true)
 }
 
+func (s *RethinkSuite) TestWriteLiteral(c *test.C) {
+	DB("test").TableDrop("test").Exec(session)
+	DB("test").TableCreate("test").Exec(session)
+	DB("test").Table("test").Wait().Exec(session)
+
+	_, err := DB("test").Table("test").Insert(map[string]interface{}{
+		"id":      "a",
+		"address": map[string]interface{}{"city": "Paris", "zip": "75001"},
+		"phone":   "555-0100",
+	}).RunWrite(session)
+	c.Assert(err, test.IsNil)
+
+	_, err = DB("test").Table("test").Get("a").Update(map[string]interface{}{
+		"address": Literal(map[string]interface{}{"city": "London"}),
+		"phone":   Literal(),
+	}).RunWrite(session)
+	c.Assert(err, test.IsNil)
+
+	var response map[string]interface{}
+	err = DB("test").Table("test").Get("a").ReadOne(&response, session)
+	c.Assert(err, test.IsNil)
+	c.Assert(response, test.DeepEquals, map[string]interface{}{
+		"id":      "a",
+		"address": map[string]interface{}{"city": "London"},
+	})
+}
+
+func (s *RethinkSuite) TestAdminTable(c *test.C) {
+	DB("test").TableDrop("test").Exec(session)
+	DB("test").TableCreate("test").Exec(session)
+
+	var wait WaitResponse
+	err := DB("test").Table("test").Wait(WaitOpts{
+		WaitFor: WaitForReadyForWrites,
+	}).ReadOne(&wait, session)
+	c.Assert(err, test.IsNil)
+	c.Assert(wait.Ready, test.Equals, 1)
+
+	var config TableConfig
+	err = DB("test").Table("test").Config().ReadOne(&config, session)
+	c.Assert(err, test.IsNil)
+	c.Assert(config.Name, test.Equals, "test")
+	c.Assert(config.DB, test.Equals, "test")
+	c.Assert(config.PrimaryKey, test.Equals, "id")
+	c.Assert(config.Shards, test.HasLen, 1)
+
+	var status TableStatus
+	err = DB("test").Table("test").Status().ReadOne(&status, session)
+	c.Assert(err, test.IsNil)
+	c.Assert(status.Name, test.Equals, "test")
+	c.Assert(status.Status.ReadyForWrites, test.Equals, true)
+	c.Assert(status.Shards, test.HasLen, 1)
+
+	var reconfigure ReconfigureResponse
+	err = DB("test").Table("test").Reconfigure(ReconfigureOpts{
+		Shards:   1,
+		Replicas: 1,
+		DryRun:   true,
+	}).ReadOne(&reconfigure, session)
+	c.Assert(err, test.IsNil)
+	c.Assert(reconfigure.Reconfigured, test.Equals, 0)
+	c.Assert(reconfigure.ConfigChanges, test.HasLen, 1)
+	c.Assert(reconfigure.ConfigChanges[0].NewValue.Name, test.Equals, "test")
+
+	var rebalance RebalanceResponse
+	err = DB("test").Table("test").Rebalance().ReadOne(&rebalance, session)
+	c.Assert(err, test.IsNil)
+	c.Assert(rebalance.Rebalanced, test.Equals, 1)
+}
+
+func (s *RethinkSuite) TestAdminGrantBuild(c *test.C) {
+	terms := map[string]Term{
+		`[188,["bob",{"connect":true}]]`:                          Grant("bob", GrantPermissions{Connect: true}),
+		`[188,[[14,["test"]],"bob",{"read":true,"write":false}]]`: DB("test").Grant("bob", GrantPermissions{Read: true, Write: false}),
+	}
+
+	for expected, term := range terms {
+		b, err := term.MarshalQuery()
+		c.Assert(err, test.IsNil)
+		c.Assert(string(b), test.Equals, expected)
+	}
+}
+
+func (s *RethinkSuite) TestWriteConflictFunc(c *test.C) {
+	DB("test").TableDrop("test").Exec(session)
+	DB("test").TableCreate("test").Exec(session)
+	DB("test").Table("test").Wait().Exec(session)
+
+	insert := func() error {
+		_, err := DB("test").Table("test").Insert(map[string]interface{}{"id": "a", "count": 1}, InsertOpts{
+			Conflict: func(id, oldDoc, newDoc Term) interface{} {
+				return newDoc.Merge(map[string]interface{}{
+					"count": oldDoc.Field("count").Add(1),
+				})
+			},
+		}).RunWrite(session)
+		return err
+	}
+
+	c.Assert(insert(), test.IsNil)
+	c.Assert(insert(), test.IsNil)
+	c.Assert(insert(), test.IsNil)
+
+	var count int
+	err := DB("test").Table("test").Get("a").Field("count").ReadOne(&count, session)
+	c.Assert(err, test.IsNil)
+	c.Assert(count, test.Equals, 3)
+}
+
 func (s *RethinkSuite) TestTimeTime(c *test.C) {
 	var response time.Time
 	res, err := Time(1986, 11, 3, 12, 30, 15, "Z").Run(session)
@@ -680,6 +1061,30 @@ func (s *RethinkSuite) TestTimeExprMillisecond(c *test.C) {
 	c.Assert(float64(response.UnixNano()), test.Equals, float64(t.UnixNano()))
 }
 
+func (s *RethinkSuite) TestTimeRoundTripTimezone(c *test.C) {
+	times := []time.Time{
+		time.Date(1986, 11, 3, 12, 30, 15, 679000000, time.UTC),
+		time.Date(1986, 11, 3, 12, 30, 15, 679000000, time.FixedZone("", 5*3600+30*60)),
+		time.Date(1986, 11, 3, 12, 30, 15, 679000000, time.FixedZone("", -7*3600)),
+		time.Date(1960, 1, 1, 0, 0, 0, 125000000, time.FixedZone("", 3600)),
+		time.Date(1500, 1, 1, 0, 0, 0, 125000000, time.UTC),
+	}
+
+	for _, t := range times {
+		built, err := Expr(t).Build()
+		c.Assert(err, test.IsNil)
+
+		obj := built.(map[string]interface{})
+		response, err := reqlTimeToNativeTime(obj["epoch_time"].(float64), obj["timezone"].(string))
+		c.Assert(err, test.IsNil)
+		c.Assert(response.Equal(t), test.Equals, true, test.Commentf("%v != %v", response, t))
+
+		_, offset := response.Zone()
+		_, expectedOffset := t.Zone()
+		c.Assert(offset, test.Equals, expectedOffset)
+	}
+}
+
 func (s *RethinkSuite) TestTimeISO8601(c *test.C) {
 	var t1, t2 time.Time
 	t2, _ = time.Parse("2006-01-02T15:04:05-07:00", "1986-11-03T08:30:00-07:00")
@@ -691,6 +1096,28 @@ func (s *RethinkSuite) TestTimeISO8601(c *test.C) {
 	c.Assert(t1.Equal(t2), test.Equals, true)
 }
 
+func (s *RethinkSuite) TestTimeISO8601DefaultTimezone(c *test.C) {
+	var t1, t2 time.Time
+	t2, _ = time.Parse("2006-01-02T15:04:05-07:00", "1986-11-03T08:30:00-07:00")
+	err := ISO8601("1986-11-03T08:30:00", ISO8601Opts{
+		DefaultTimezone: "-07:00",
+	}).ReadOne(&t1, session)
+	c.Assert(err, test.IsNil)
+	c.Assert(t1.Equal(t2), test.Equals, true)
+}
+
+func (s *RethinkSuite) TestTimeDuring(c *test.C) {
+	var response []bool
+	start, end := Time(1986, 11, 3, "Z"), Time(1986, 11, 4, "Z")
+	err := Expr([]interface{}{
+		start.During(start, end),
+		start.During(start, end, DuringOpts{LeftBound: BoundOpen}),
+		end.During(start, end, DuringOpts{RightBound: BoundClosed}),
+	}).ReadAll(&response, session)
+	c.Assert(err, test.IsNil)
+	c.Assert(response, test.DeepEquals, []bool{true, false, true})
+}
+
 func (s *RethinkSuite) TestTimeInTimezone(c *test.C) {
 	var response []time.Time
 	res, err := Expr([]interface{}{Now(), Now().InTimezone("-07:00")}).Run(session)
@@ -701,6 +1128,537 @@ func (s *RethinkSuite) TestTimeInTimezone(c *test.C) {
 	c.Assert(response[1].Equal(response[0]), test.Equals, true)
 }
 
+func (s *RethinkSuite) TestStringMatch(c *test.C) {
+	var response *MatchResult
+	err := Expr("id:0,name:mlucy,foo:bar").Match("name:(\\w+)(x)?").ReadOne(&response, session)
+	c.Assert(err, test.IsNil)
+	c.Assert(response, test.DeepEquals, &MatchResult{
+		Str:   "name:mlucy",
+		Start: 5,
+		End:   15,
+		Groups: []*MatchGroup{
+			{Str: "mlucy", Start: 10, End: 15},
+			nil,
+		},
+	})
+
+	response = nil
+	err = Expr("foo").Match("bar").ReadOne(&response, session)
+	c.Assert(err, test.IsNil)
+	c.Assert(response, test.IsNil)
+}
+
+func (s *RethinkSuite) TestMathRandomBuild(c *test.C) {
+	terms := map[string]Term{
+		`[151]`:                       Random(),
+		`[151,[10]]`:                  Random(10),
+		`[151,[1,10],{"float":true}]`: Random(1, 10, RandomOpts{Float: true}),
+	}
+
+	for expected, term := range terms {
+		b, err := term.MarshalQuery()
+		c.Assert(err, test.IsNil)
+		c.Assert(string(b), test.Equals, expected)
+	}
+}
+
+func (s *RethinkSuite) TestMathBitwiseBuild(c *test.C) {
+	terms := map[string]Term{
+		`[191,[5,3]]`:         Expr(5).BitAnd(3),
+		`[192,[5,3,8]]`:       BitOr(5, 3, 8),
+		`[193,[5,3]]`:         Expr(5).BitXor(3),
+		`[194,[5]]`:           BitNot(5),
+		`[195,[1,4]]`:         Expr(1).BitSal(4),
+		`[196,[-16,2]]`:       BitSar(-16, 2),
+		`[194,[[191,[7,3]]]]`: Expr(7).BitAnd(3).BitNot(),
+	}
+
+	for expected, term := range terms {
+		b, err := term.MarshalQuery()
+		c.Assert(err, test.IsNil)
+		c.Assert(string(b), test.Equals, expected)
+	}
+}
+
+func (s *RethinkSuite) TestSelectCompoundKeyBuild(c *test.C) {
+	terms := map[string]Term{
+		`[16,[[15,["scores"]],[2,["player",1]]]]`:                   Table("scores").Get(CompoundKey("player", 1)),
+		`[78,[[15,["scores"]],[2,["a",1]],[2,["b",2]]]]`:            Table("scores").GetAll(CompoundKey("a", 1), CompoundKey("b", 2)),
+		`[182,[[15,["scores"]],[2,["a",1]],[2,["a",[181]]]]]`:       Table("scores").Between(CompoundKey("a", 1), CompoundKey("a", MaxVal)),
+		`[78,[[15,["scores"]],[2,["a",1]]],{"index":"player_lvl"}]`: Table("scores").GetAll(CompoundKey("a", 1), GetAllOpts{Index: "player_lvl"}),
+	}
+
+	for expected, term := range terms {
+		b, err := term.MarshalQuery()
+		c.Assert(err, test.IsNil)
+		c.Assert(string(b), test.Equals, expected)
+	}
+
+	_, err := Table("scores").Get(CompoundKey()).Build()
+	c.Assert(err, test.ErrorMatches, "gorethink: CompoundKey requires at least one part")
+
+	// Compound keys are decoded into the tagged struct fields
+	type Score struct {
+		Player string `gorethink:"id[0]"`
+		Level  int    `gorethink:"id[1]"`
+	}
+	var score Score
+	c.Assert(encoding.Decode(&score, map[string]interface{}{"id": []interface{}{"player", float64(1)}}), test.IsNil)
+	c.Assert(score, test.Equals, Score{"player", 1})
+}
+
+func (s *RethinkSuite) TestSelectBetweenBuild(c *test.C) {
+	query := Table("test").Between(1, 10, BetweenOpts{
+		Index:      "n",
+		LeftBound:  BoundOpen,
+		RightBound: BoundClosed,
+	})
+
+	b, err := query.MarshalQuery()
+	c.Assert(err, test.IsNil)
+	c.Assert(string(b), test.Equals, `[182,[[15,["test"]],1,10],{"index":"n","left_bound":"open","right_bound":"closed"}]`)
+
+	// MinVal and MaxVal leave either end of the range unbounded
+	b, err = Table("test").Between(MinVal, 10).MarshalQuery()
+	c.Assert(err, test.IsNil)
+	c.Assert(string(b), test.Equals, `[182,[[15,["test"]],[180],10]]`)
+
+	b, err = Table("test").Between(1, MaxVal).MarshalQuery()
+	c.Assert(err, test.IsNil)
+	c.Assert(string(b), test.Equals, `[182,[[15,["test"]],1,[181]]]`)
+
+	// Invalid bounds are rejected before the query is sent
+	_, err = Table("test").Between(1, 10, BetweenOpts{LeftBound: "opened"}).Build()
+	c.Assert(err, test.NotNil)
+
+	_, err = Table("test").Between(1, 10, BetweenOpts{RightBound: "close"}).Filter(Row).Build()
+	c.Assert(err, test.NotNil)
+}
+
+func (s *RethinkSuite) TestTransformationOrderByBuild(c *test.C) {
+	query := Table("test").OrderBy(Desc(func(row Term) Term {
+		return row.Field("n")
+	}), OrderByOpts{Index: Asc("id")})
+
+	b, err := query.MarshalQuery()
+	c.Assert(err, test.IsNil)
+	c.Assert(string(b), test.Matches, `\[41,\[\[15,\["test"\]\],\[74,\[\[69,.*\]\]\]\],\{"index":\[73,\["id"\]\]\}\]`)
+
+	// Only a single index can be used
+	_, err = Table("test").OrderBy(OrderByOpts{Index: Desc("a", "b")}).Build()
+	c.Assert(err, test.NotNil)
+
+	_, err = Table("test").OrderBy(OrderByOpts{Index: "a"}, OrderByOpts{Index: "b"}).Build()
+	c.Assert(err, test.NotNil)
+}
+
+func (s *RethinkSuite) TestSelectGetAllBuild(c *test.C) {
+	terms := map[string]Term{
+		`[78,[[15,["test"]],"a","b"]]`:                                    Table("test").GetAll("a", "b"),
+		`[78,[[15,["test"]],"a","b"],{"index":"name"}]`:                   Table("test").GetAll("a", "b", GetAllOpts{Index: "name"}),
+		`[78,[[15,["test"]],[2,["John","Smith"]]],{"index":"full_name"}]`: Table("test").GetAllByIndex("full_name", []string{"John", "Smith"}),
+	}
+
+	for expected, term := range terms {
+		b, err := term.MarshalQuery()
+		c.Assert(err, test.IsNil)
+		c.Assert(string(b), test.Equals, expected)
+	}
+}
+
+func (s *RethinkSuite) TestTransformationMapMultipleBuild(c *test.C) {
+	terms := []Term{
+		Map([]int{1}, []int{2}, func(a, b Term) Term { return a.Add(b) }),
+		Expr([]int{1}).Map([]int{2}, func(a, b Term) Term { return a.Add(b) }),
+	}
+
+	for _, term := range terms {
+		b, err := term.MarshalQuery()
+		c.Assert(err, test.IsNil)
+		c.Assert(string(b), test.Matches, `\[38,\[\[2,\[1\]\],\[2,\[2\]\],\[69,\[\[2,\[\d+,\d+\]\],\[24,\[\[10,\[\d+\]\],\[10,\[\d+\]\]\]\]\]\]\]\]`)
+	}
+}
+
+func (s *RethinkSuite) TestAggregationGroupBuild(c *test.C) {
+	terms := map[string]Term{
+		`[43,[[144,[[15,["users"]],"country"]]]]`:                Table("users").Group("country").Count(),
+		`[145,[[144,[[15,["users"]],"country"]],"amount"]]`:      Table("users").Group("country").Sum("amount"),
+		`[150,[[146,[[144,[[15,["users"]],"country"]],"age"]]]]`: Table("users").Group("country").Avg("age").Ungroup(),
+	}
+
+	for expected, term := range terms {
+		b, err := term.MarshalQuery()
+		c.Assert(err, test.IsNil)
+		c.Assert(string(b), test.Equals, expected)
+	}
+}
+
+func (s *RethinkSuite) TestAggregationGroupedResultDecode(c *test.C) {
+	data, err := recursivelyConvertPseudotype(map[string]interface{}{
+		"$reql_type$": "GROUPED_DATA",
+		"data": []interface{}{
+			[]interface{}{"UK", float64(2)},
+			[]interface{}{"US", float64(3)},
+		},
+	}, map[string]interface{}{})
+	c.Assert(err, test.IsNil)
+
+	var res []GroupedResult
+	c.Assert(encoding.Decode(&res, data), test.IsNil)
+	c.Assert(res, test.DeepEquals, []GroupedResult{
+		{Group: "UK", Reduction: float64(2)},
+		{Group: "US", Reduction: float64(3)},
+	})
+
+	var typed []struct {
+		Group     string `gorethink:"group"`
+		Reduction int    `gorethink:"reduction"`
+	}
+	c.Assert(encoding.Decode(&typed, data), test.IsNil)
+	c.Assert(typed, test.HasLen, 2)
+	c.Assert(typed[1].Group, test.Equals, "US")
+	c.Assert(typed[1].Reduction, test.Equals, 3)
+}
+
+func (s *RethinkSuite) TestJoinEqJoinBuild(c *test.C) {
+	term := Table("posts").EqJoin("author_id", Table("users"), EqJoinOpts{Index: "id", Ordered: true})
+	b, err := term.MarshalQuery()
+	c.Assert(err, test.IsNil)
+	c.Assert(string(b), test.Equals, `[50,[[15,["posts"]],"author_id",[15,["users"]]],{"index":"id","ordered":true}]`)
+
+	term = Table("posts").EqJoin(func(post Term) interface{} {
+		return post.Field("author").Field("id")
+	}, Table("users"))
+	b, err = term.MarshalQuery()
+	c.Assert(err, test.IsNil)
+	c.Assert(string(b), test.Matches, `\[50,\[\[15,\["posts"\]\],\[69,\[\[2,\[\d+\]\],\[31,\[\[31,\[\[10,\[\d+\]\],"author"\]\],"id"\]\]\]\],\[15,\["users"\]\]\]\]`)
+
+	_, err = Table("posts").EqJoin(func(a, b Term) interface{} { return a }, Table("users")).Build()
+	c.Assert(err, test.ErrorMatches, "gorethink: EqJoin function takes 2 arguments but was given 1")
+}
+
+func (s *RethinkSuite) TestAggregationGroupedDataMapDecode(c *test.C) {
+	opts := map[string]interface{}{"group_format": "map"}
+	data, err := recursivelyConvertPseudotype(map[string]interface{}{
+		"$reql_type$": "GROUPED_DATA",
+		"data": []interface{}{
+			[]interface{}{"UK", float64(2)},
+			[]interface{}{"US", float64(3)},
+		},
+	}, opts)
+	c.Assert(err, test.IsNil)
+
+	var res map[string]int
+	c.Assert(encoding.Decode(&res, data), test.IsNil)
+	c.Assert(res, test.DeepEquals, map[string]int{"UK": 2, "US": 3})
+
+	// Groups of multiple fields can not be used as map keys
+	_, err = recursivelyConvertPseudotype(map[string]interface{}{
+		"$reql_type$": "GROUPED_DATA",
+		"data": []interface{}{
+			[]interface{}{[]interface{}{"UK", "London"}, float64(2)},
+		},
+	}, opts)
+	c.Assert(err, test.ErrorMatches, "group .* can not be used as a map key, use the slice group_format instead")
+}
+
+func (s *RethinkSuite) TestTransformationUnionBuild(c *test.C) {
+	terms := map[string]Term{
+		`[44,[[2,[1,2]],[2,[3]]]]`:                      Expr([]int{1, 2}).Union([]int{3}),
+		`[44,[[2,[1,2]],[2,[3]]],{"interleave":false}]`: Expr([]int{1, 2}).Union([]int{3}, UnionOpts{Interleave: false}),
+		`[44,[[2,[1,2]],[2,[3]]],{"interleave":"n"}]`:   Union([]int{1, 2}, []int{3}, UnionOpts{Interleave: "n"}),
+	}
+
+	for expected, term := range terms {
+		b, err := term.MarshalQuery()
+		c.Assert(err, test.IsNil)
+		c.Assert(string(b), test.Equals, expected)
+	}
+
+	// Functions can be used to merge sorted sequences
+	b, err := Table("a").Union(Table("b"), UnionOpts{
+		Interleave: func(row Term) Term { return row.Field("n") },
+	}).MarshalQuery()
+	c.Assert(err, test.IsNil)
+	c.Assert(string(b), test.Matches, `.*"interleave":\[69,.*`)
+}
+
+func (s *RethinkSuite) TestTermString(c *test.C) {
+	terms := map[string]Term{
+		`r.Table("users").Filter({age=30})`: Table("users").Filter(map[string]interface{}{"age": 30}),
+		`[1, "a", nil]`:                     Expr([]interface{}{1, "a", nil}),
+		`r.Binary(<data>)`:                  Binary([]byte("abc")),
+		`r.DB("test").Table("t").Between(r.MinVal, r.MaxVal, index="n", left_bound="open")`: DB("test").Table("t").Between(MinVal, MaxVal, BetweenOpts{
+			Index:     "n",
+			LeftBound: BoundOpen,
+		}),
+		`r.Table("t").Insert({a=1, b=2, c=3}, conflict="replace", durability="soft", return_changes=true)`: Table("t").Insert(map[string]interface{}{"c": 3, "b": 2, "a": 1}, InsertOpts{
+			Durability:    "soft",
+			ReturnChanges: true,
+			Conflict:      ConflictReplace,
+		}),
+	}
+
+	for expected, term := range terms {
+		// Optional arguments are stored in a map so check the output is stable
+		for i := 0; i < 10; i++ {
+			c.Assert(term.String(), test.Equals, expected)
+		}
+	}
+
+	term := Table("users").Filter(func(u Term) Term { return u.Field("age").Gt(30) }).Limit(2)
+	c.Assert(term.String(), test.Matches, `r\.Table\("users"\)\.Filter\(func\(var_\d+ r\.Term\) r\.Term \{ return var_\d+\.Field\("age"\)\.Gt\(30\) \}\)\.Limit\(2\)`)
+}
+
+func (s *RethinkSuite) TestTermValidate(c *test.C) {
+	valid := []Term{
+		DB("test").Table("t").GetAll(1, 2, GetAllOpts{Index: "n"}).Filter(Row.Field("a").Eq(1)).Limit(2),
+		Table("t").Between(MinVal, MaxVal, BetweenOpts{Index: "n"}),
+		Table("t").Insert(map[string]interface{}{"a": 1}, InsertOpts{Conflict: ConflictReplace}),
+		Branch(Expr(1).Gt(0), "a", Expr(2).Lt(1), "b", "c"),
+		Do(1, 2, func(a, b Term) Term { return a.Add(b) }),
+		Time(2014, 7, 12, "Z"),
+		Time(Args([]interface{}{2014, 7, 12, "Z"})),
+		Expr("a b").Split(" ", 5),
+	}
+
+	for _, term := range valid {
+		c.Assert(term.Validate(), test.IsNil)
+	}
+
+	invalid := map[string]Term{
+		`Limit: Expected 1 argument but found 2`:                           Table("t").Limit(1, 2),
+		`Time: Expected between 4 and 7 arguments but found 2`:             Time(2014, 7),
+		`Table: Expected argument 1 to be a string but found 1`:            DB("test").Table(1),
+		`Nth: Expected argument 1 to be a number but found "x"`:            Expr([]int{1}).Nth("x"),
+		`Filter: Unrecognized optional argument "defualt", expected one.*`: Table("t").Filter(Row).OptArgs(map[string]interface{}{"defualt": true}),
+		`Get: Expected 1 argument but found 0`:                             Table("t").Get().Field("a"),
+		`Branch: Cannot call Branch with an even number of arguments`:      Branch(true, 1, true, 2),
+	}
+
+	for expected, term := range invalid {
+		err := term.Validate()
+		c.Assert(err, test.FitsTypeOf, RQLDriverError{})
+		c.Assert(err, test.ErrorMatches, "gorethink: "+expected+" in:\n.*")
+	}
+
+	// Errors recorded while building the query are returned
+	err := Table("t").Between(1, 2, BetweenOpts{LeftBound: "opened"}).Validate()
+	c.Assert(err, test.NotNil)
+}
+
+func (s *RethinkSuite) TestTermMarshalQuery(c *test.C) {
+	b, err := Table("users").Get("alice").MarshalQuery()
+	c.Assert(err, test.IsNil)
+	c.Assert(string(b), test.Equals, `[16,[[15,["users"]],"alice"]]`)
+
+	// Optional arguments and objects are encoded with sorted keys
+	query := Table("users").Insert(map[string]interface{}{"name": "alice", "age": 30}, InsertOpts{
+		ReturnChanges: true,
+		Durability:    "soft",
+	})
+	for i := 0; i < 10; i++ {
+		b, err = query.MarshalQuery()
+		c.Assert(err, test.IsNil)
+		c.Assert(string(b), test.Equals, `[56,[[15,["users"]],{"age":30,"name":"alice"}],{"durability":"soft","return_changes":true}]`)
+	}
+
+	// Errors recorded while building the query are returned
+	_, err = Table("users").Between(1, 2, BetweenOpts{LeftBound: "opened"}).MarshalQuery()
+	c.Assert(err, test.NotNil)
+}
+
+func (s *RethinkSuite) TestTermUnmarshalQuery(c *test.C) {
+	queries := []Term{
+		Table("users").Get("alice"),
+		DB("test").Table("users").Filter(func(user Term) Term { return user.Field("age").Gt(30) }).Limit(2),
+		Table("users").Insert(map[string]interface{}{"name": "alice", "tags": []interface{}{1, "a"}}, InsertOpts{Conflict: ConflictReplace}),
+		Expr([]int{1, 2}).Fold(0, func(acc, n Term) Term { return acc.Add(n) }),
+		Expr(1.5),
+	}
+
+	// Parsing a marshaled query produces the same query
+	for _, query := range queries {
+		b, err := query.MarshalQuery()
+		c.Assert(err, test.IsNil)
+
+		parsed, err := UnmarshalQuery(b)
+		c.Assert(err, test.IsNil)
+
+		b2, err := parsed.MarshalQuery()
+		c.Assert(err, test.IsNil)
+		c.Assert(string(b2), test.Matches, regexp.MustCompile(`\d+`).ReplaceAllString(regexp.QuoteMeta(string(b)), `\d+`))
+	}
+
+	// Parsed terms can be extended
+	parsed, err := UnmarshalQuery([]byte(`[15,["users"]]`))
+	c.Assert(err, test.IsNil)
+	c.Assert(parsed.Get("alice").String(), test.Equals, `r.Table("users").Get("alice")`)
+
+	b, err := parsed.Get("alice").MarshalQuery()
+	c.Assert(err, test.IsNil)
+	c.Assert(string(b), test.Equals, `[16,[[15,["users"]],"alice"]]`)
+
+	// Function variables are renumbered
+	parsed, err = UnmarshalQuery([]byte(`[69,[[2,[1]],[10,[1]]]]`))
+	c.Assert(err, test.IsNil)
+	c.Assert(parsed.args[0].args[0].data, test.Equals, parsed.args[1].args[0].data)
+	c.Assert(parsed.args[0].args[0].data, test.Not(test.Equals), int64(1))
+
+	invalid := []string{`[]`, `[999]`, `["x"]`, `[15,"users"]`, `[69,[1,2]]`, `{`}
+	for _, s := range invalid {
+		_, err := UnmarshalQuery([]byte(s))
+		c.Assert(err, test.FitsTypeOf, RQLDriverError{})
+	}
+}
+
+func (s *RethinkSuite) TestTermPrepare(c *test.C) {
+	query, err := Table("users").GetAll(Param("name"), GetAllOpts{Index: "name"}).Filter(func(user Term) Term {
+		return user.Field("age").Gt(Param("age"))
+	}).Limit(Param("limit")).Prepare()
+	c.Assert(err, test.IsNil)
+
+	params := map[string]interface{}{"name": "alice", "age": 30, "limit": 10}
+	b, err := query.Bind(params).MarshalQuery()
+	c.Assert(err, test.IsNil)
+	c.Assert(string(b), test.Matches, `\[71,\[\[39,\[\[78,\[\[15,\["users"\]\],"alice"\],\{"index":"name"\}\],\[69,\[\[2,\[\d+\]\],\[21,\[\[31,\[\[10,\[\d+\]\],"age"\]\],30\]\]\]\]\]\],10\]\]`)
+
+	// Parameters can be used more than once and bound to any value
+	query, err = Expr([]interface{}{Param("a"), Param("b"), Param("a")}).Prepare()
+	c.Assert(err, test.IsNil)
+
+	b, err = query.Bind(map[string]interface{}{"a": []int{1, 2}, "b": map[string]interface{}{"c": nil}}).MarshalQuery()
+	c.Assert(err, test.IsNil)
+	c.Assert(string(b), test.Equals, `[2,[[2,[1,2]],{"c":null},[2,[1,2]]]]`)
+
+	// Missing and unknown parameters are errors
+	_, err = query.Bind(map[string]interface{}{"a": 1}).Build()
+	c.Assert(err, test.ErrorMatches, `gorethink: Missing query parameter "b"`)
+
+	_, err = query.Bind(map[string]interface{}{"a": 1, "b": 2, "c": 3}).Build()
+	c.Assert(err, test.ErrorMatches, `gorethink: Unknown query parameter "c"`)
+
+	// Queries containing parameters must be prepared
+	_, err = Table("users").Get(Param("id")).MarshalQuery()
+	c.Assert(err, test.ErrorMatches, `.*unbound query parameter "id".*`)
+	c.Assert(Table("users").Get(Param("id")).String(), test.Equals, `r.Table("users").Get(r.Param("id"))`)
+}
+
+func (s *RethinkSuite) TestTermOptArgNames(c *test.C) {
+	// Every field of the Opts structs must be accepted by its term
+	opts := map[p.Term_TermType]interface{}{
+		p.Term_TABLE:            TableOpts{},
+		p.Term_GET_ALL:          GetAllOpts{},
+		p.Term_BETWEEN:          BetweenOpts{},
+		p.Term_FILTER:           FilterOpts{},
+		p.Term_SLICE:            SliceOpts{},
+		p.Term_ORDER_BY:         OrderByOpts{},
+		p.Term_UNION:            UnionOpts{},
+		p.Term_DISTINCT:         DistinctOpts{},
+		p.Term_GROUP:            GroupOpts{},
+		p.Term_MIN:              MinOpts{},
+		p.Term_MAX:              MaxOpts{},
+		p.Term_FOLD:             FoldOpts{},
+		p.Term_EQ_JOIN:          EqJoinOpts{},
+		p.Term_JAVASCRIPT:       JSOpts{},
+		p.Term_HTTP:             HTTPOpts{},
+		p.Term_RANDOM:           RandomOpts{},
+		p.Term_ISO8601:          ISO8601Opts{},
+		p.Term_DURING:           DuringOpts{},
+		p.Term_CIRCLE:           CircleOpts{},
+		p.Term_DISTANCE:         DistanceOpts{},
+		p.Term_GET_INTERSECTING: GetIntersectingOpts{},
+		p.Term_GET_NEAREST:      GetNearestOpts{},
+		p.Term_TABLE_CREATE:     TableCreateOpts{},
+		p.Term_INDEX_CREATE:     IndexCreateOpts{},
+		p.Term_INDEX_RENAME:     IndexRenameOpts{},
+		p.Term_CHANGES:          ChangesOpts{},
+		p.Term_RECONFIGURE:      ReconfigureOpts{},
+		p.Term_WAIT:             WaitOpts{},
+		p.Term_INSERT:           InsertOpts{},
+		p.Term_UPDATE:           UpdateOpts{},
+		p.Term_REPLACE:          ReplaceOpts{},
+		p.Term_DELETE:           DeleteOpts{},
+	}
+
+	for termType, o := range opts {
+		typ := reflect.TypeOf(o)
+		for i := 0; i < typ.NumField(); i++ {
+			name := strings.Split(typ.Field(i).Tag.Get("gorethink"), ",")[0]
+			if name == "" || name == "-" {
+				continue
+			}
+			c.Assert(containsString(termOptArgs[termType], name), test.Equals, true, test.Commentf("%s.%s", typ, name))
+		}
+	}
+
+	// Optional arguments passed as a map are checked
+	_, err := Table("users").Insert(map[string]interface{}{}).OptArgs(map[string]interface{}{"durable": "soft"}).Build()
+	c.Assert(err, test.ErrorMatches, `(?s)gorethink: Insert: Unrecognized optional argument "durable".*`)
+
+	_, err = Table("users").Insert(map[string]interface{}{}).OptArgs(map[string]interface{}{"ignore_write_hook": true}).Build()
+	c.Assert(err, test.IsNil)
+
+	_, err = Table("users").Insert(map[string]interface{}{}).OptArgs(InsertOpts{}).Build()
+	c.Assert(err, test.IsNil)
+
+	_, err = Table("users").Insert(map[string]interface{}{}).OptArgs(struct{}{}).Build()
+	c.Assert(err, test.ErrorMatches, `gorethink: Unsupported optional arguments type struct \{\}.*`)
+}
+
+func (s *RethinkSuite) TestTermImmutable(c *test.C) {
+	// Check is used as the queries are also built from other goroutines
+	buildJSON := func(t Term) string {
+		b, err := t.MarshalQuery()
+		c.Check(err, test.IsNil)
+		return string(b)
+	}
+
+	base := Table("users").Filter(Row.Field("active"))
+	expected := buildJSON(base)
+
+	// Extending the base query concurrently does not modify it
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			buildJSON(base.Limit(i).OptArgs(map[string]interface{}{"i": i}))
+			buildJSON(base.OrderBy("name", OrderByOpts{Index: "id"}))
+		}(i)
+	}
+	wg.Wait()
+	c.Assert(buildJSON(base), test.Equals, expected)
+
+	// Arguments passed as a slice are not modified
+	fields := []interface{}{"name", func(row Term) Term { return row.Field("age") }}
+	buildJSON(base.OrderBy(fields...))
+	buildJSON(base.Group(fields...))
+	buildJSON(Map(fields...))
+	_, ok := fields[1].(func(Term) Term)
+	c.Assert(ok, test.Equals, true)
+}
+
+func (s *RethinkSuite) TestTableWriteHookBuild(c *test.C) {
+	terms := map[string]Term{
+		`[189,[[15,["test"]],null]]`: Table("test").SetWriteHook(nil),
+		`[190,[[15,["test"]]]]`:      Table("test").GetWriteHook(),
+	}
+
+	for expected, term := range terms {
+		b, err := term.MarshalQuery()
+		c.Assert(err, test.IsNil)
+		c.Assert(string(b), test.Equals, expected)
+	}
+
+	b, err := Table("test").SetWriteHook(func(ctx, oldVal, newVal Term) interface{} {
+		return newVal.Merge(map[string]interface{}{"modified_at": ctx.Field("timestamp")})
+	}).MarshalQuery()
+	c.Assert(err, test.IsNil)
+	c.Assert(string(b), test.Matches, `\[189,\[\[15,\["test"\]\],\[69,\[\[2,\[\d+,\d+,\d+\]\],.*`)
+}
+
 func (s *RethinkSuite) TestSelectJSONNumbers(c *test.C) {
 	session, err := Connect(ConnectOpts{
 		Address:       url,
@@ -28,9 +28,10 @@ func (o ISO8601Opts) toMap() map[string]interface{} {
 	return optArgsToMap(o)
 }
 
-// ISO8601 returns a time object based on an ISO8601 formatted date-time string
+// ISO8601 returns a time object based on an ISO8601 formatted date-time string.
+// If the string does not include a timezone then the DefaultTimezone optional
+// argument must be set.
 func ISO8601(date interface{}, optArgs ...ISO8601Opts) Term {
-
 	opts := map[string]interface{}{}
 	if len(optArgs) >= 1 {
 		opts = optArgs[0].toMap()
@@ -62,7 +63,8 @@ func (o DuringOpts) toMap() map[string]interface{} {
 }
 
 // During returns true if a time is between two other times
-// (by default, inclusive for the start, exclusive for the end).
+// (by default, inclusive for the start, exclusive for the end). The bounds
+// can be changed using BoundOpen and BoundClosed.
 func (t Term) During(startTime, endTime interface{}, optArgs ...DuringOpts) Term {
 	opts := map[string]interface{}{}
 	if len(optArgs) >= 1 {
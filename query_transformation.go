@@ -1,11 +1,20 @@
 package gorethink
 
-import p "gopkg.in/gorethink/gorethink.v3/ql2"
+import (
+	"fmt"
+
+	p "gopkg.in/gorethink/gorethink.v3/ql2"
+)
 
 // Map transform each element of the sequence by applying the given mapping
 // function. It takes two arguments, a sequence and a function of type
 // `func (r.Term) interface{}`.
 //
+// Multiple sequences can be passed before the function, in which case the
+// function must take one argument per sequence and is called with an element
+// from each. The result is as long as the shortest sequence. Building the query
+// fails if the function takes the wrong number of arguments.
+//
 // For example this query doubles each element in an array:
 //
 //     r.Map([]int{1,3,6}, func (row r.Term) interface{} {
@@ -13,7 +22,15 @@ import p "gopkg.in/gorethink/gorethink.v3/ql2"
 //     })
 func Map(args ...interface{}) Term {
 	if len(args) > 0 {
-		args = append(args[:len(args)-1], funcWrap(args[len(args)-1]))
+		f := args[len(args)-1]
+		// Limit the capacity so append copies rather than modifying the caller's slice
+		args = append(args[:len(args)-1:len(args)-1], funcWrap(f))
+
+		term := constructRootTerm("Map", p.Term_MAP, args, map[string]interface{}{})
+		if err := checkFuncArg("Map", f, len(args)-1); err != nil {
+			term.lastErr = err
+		}
+		return term
 	}
 
 	return constructRootTerm("Map", p.Term_MAP, args, map[string]interface{}{})
@@ -22,6 +39,13 @@ func Map(args ...interface{}) Term {
 // Map transforms each element of the sequence by applying the given mapping
 // function. It takes one argument of type `func (r.Term) interface{}`.
 //
+// Additional sequences can be passed before the function, which must then take
+// one argument per sequence, for example:
+//
+//     r.Expr([]int{1, 2, 3}).Map([]int{10, 20, 30}, func(a, b r.Term) interface{} {
+//         return a.Add(b)
+//     })
+//
 // For example this query doubles each element in an array:
 //
 //     r.Expr([]int{1,3,6}).Map(func (row r.Term) interface{} {
@@ -29,7 +53,15 @@ func Map(args ...interface{}) Term {
 //     })
 func (t Term) Map(args ...interface{}) Term {
 	if len(args) > 0 {
-		args = append(args[:len(args)-1], funcWrap(args[len(args)-1]))
+		f := args[len(args)-1]
+		// Limit the capacity so append copies rather than modifying the caller's slice
+		args = append(args[:len(args)-1:len(args)-1], funcWrap(f))
+
+		term := constructMethodTerm(t, "Map", p.Term_MAP, args, map[string]interface{}{})
+		if err := checkFuncArg("Map", f, len(args)); err != nil {
+			term.lastErr = err
+		}
+		return term
 	}
 
 	return constructMethodTerm(t, "Map", p.Term_MAP, args, map[string]interface{}{})
@@ -53,6 +85,8 @@ func (t Term) ConcatMap(args ...interface{}) Term {
 
 // OrderByOpts contains the optional arguments for the OrderBy term
 type OrderByOpts struct {
+	// Index is the name of the index to sort by, it can be wrapped with Asc
+	// or Desc to specify the ordering.
 	Index interface{} `gorethink:"index,omitempty"`
 }
 
@@ -60,6 +94,14 @@ func (o OrderByOpts) toMap() map[string]interface{} {
 	return optArgsToMap(o)
 }
 
+func (o OrderByOpts) validate() error {
+	if t, ok := o.Index.(Term); ok && (t.termType == p.Term_DESC || t.termType == p.Term_ASC) && len(t.args) != 1 {
+		return RQLDriverError{rqlError(fmt.Sprintf("%s must be given exactly one index, got %d", t.name, len(t.args)))}
+	}
+
+	return nil
+}
+
 // OrderBy sorts the sequence by document values of the given key(s). To specify
 // the ordering, wrap the attribute with either r.Asc or r.Desc (defaults to
 // ascending).
@@ -70,22 +112,36 @@ func (o OrderByOpts) toMap() map[string]interface{} {
 // after a between command using the same index.
 func (t Term) OrderBy(args ...interface{}) Term {
 	var opts = map[string]interface{}{}
+	var err error
 
 	// Look for options map
 	if len(args) > 0 {
 		if possibleOpts, ok := args[len(args)-1].(OrderByOpts); ok {
 			opts = possibleOpts.toMap()
+			err = possibleOpts.validate()
 			args = args[:len(args)-1]
 		}
 	}
 
+	// Copy the arguments so the caller's slice is not modified
+	args = append([]interface{}{}, args...)
 	for k, arg := range args {
-		if t, ok := arg.(Term); !(ok && (t.termType == p.Term_DESC || t.termType == p.Term_ASC)) {
+		switch arg := arg.(type) {
+		case OrderByOpts:
+			err = RQLDriverError{rqlError("OrderBy accepts a single OrderByOpts as its last argument")}
+		case Term:
+			if arg.termType != p.Term_DESC && arg.termType != p.Term_ASC {
+				args[k] = funcWrap(arg)
+			}
+		default:
 			args[k] = funcWrap(arg)
 		}
 	}
 
-	return constructMethodTerm(t, "OrderBy", p.Term_ORDER_BY, args, opts)
+	term := constructMethodTerm(t, "OrderBy", p.Term_ORDER_BY, args, opts)
+	term.lastErr = err
+
+	return term
 }
 
 // Desc is used by the OrderBy term to specify the ordering to be descending.
@@ -155,8 +211,12 @@ func (t Term) IsEmpty(args ...interface{}) Term {
 	return constructMethodTerm(t, "IsEmpty", p.Term_IS_EMPTY, args, map[string]interface{}{})
 }
 
-// UnionOpts contains the optional arguments for the Slice term
+// UnionOpts contains the optional arguments for the Union term
 type UnionOpts struct {
+	// Interleave controls how the sequences are merged. If true (the default)
+	// elements are interleaved as they arrive, if false the sequences are
+	// concatenated in order. If a field name, function or Asc/Desc ordering is
+	// given then sorted sequences are merged into a single sorted sequence.
 	Interleave interface{} `gorethink:"interleave,omitempty"`
 }
 
@@ -164,14 +224,29 @@ func (o UnionOpts) toMap() map[string]interface{} {
 	return optArgsToMap(o)
 }
 
-// Union concatenates two sequences.
+// unionArgs removes UnionOpts from the end of the arguments to Union.
+func unionArgs(args []interface{}) ([]interface{}, map[string]interface{}) {
+	if len(args) > 0 {
+		if opts, ok := args[len(args)-1].(UnionOpts); ok {
+			return args[:len(args)-1], opts.toMap()
+		}
+	}
+
+	return args, map[string]interface{}{}
+}
+
+// Union concatenates two sequences. A UnionOpts value can be passed as the
+// last argument.
 func Union(args ...interface{}) Term {
-	return constructRootTerm("Union", p.Term_UNION, args, map[string]interface{}{})
+	args, opts := unionArgs(args)
+	return constructRootTerm("Union", p.Term_UNION, args, opts)
 }
 
-// Union concatenates two sequences.
+// Union concatenates two sequences. A UnionOpts value can be passed as the
+// last argument.
 func (t Term) Union(args ...interface{}) Term {
-	return constructMethodTerm(t, "Union", p.Term_UNION, args, map[string]interface{}{})
+	args, opts := unionArgs(args)
+	return constructMethodTerm(t, "Union", p.Term_UNION, args, opts)
 }
 
 // UnionWithOpts like Union concatenates two sequences however allows for optional
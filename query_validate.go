@@ -0,0 +1,402 @@
+package gorethink
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	p "gopkg.in/gorethink/gorethink.v3/ql2"
+)
+
+// variadic is used as the maximum number of arguments of terms which accept
+// any number of arguments.
+const variadic = -1
+
+// termArity contains the minimum and maximum number of arguments accepted by
+// each term (including the term the method was called on) as documented in
+// ql2.proto. Terms which are not listed are not checked.
+var termArity = map[p.Term_TermType][2]int{
+	p.Term_IMPLICIT_VAR:     {0, 0},
+	p.Term_VAR:              {1, 1},
+	p.Term_FUNC:             {2, 2},
+	p.Term_DB:               {1, 1},
+	p.Term_TABLE:            {1, 2},
+	p.Term_GET:              {2, 2},
+	p.Term_GET_ALL:          {1, variadic},
+	p.Term_BETWEEN:          {3, 3},
+	p.Term_FILTER:           {2, 2},
+	p.Term_GET_FIELD:        {2, 2},
+	p.Term_BRACKET:          {2, 2},
+	p.Term_NTH:              {2, 2},
+	p.Term_LIMIT:            {2, 2},
+	p.Term_SKIP:             {2, 2},
+	p.Term_SLICE:            {2, 3},
+	p.Term_SAMPLE:           {2, 2},
+	p.Term_EQ:               {1, variadic},
+	p.Term_NE:               {1, variadic},
+	p.Term_LT:               {1, variadic},
+	p.Term_LE:               {1, variadic},
+	p.Term_GT:               {1, variadic},
+	p.Term_GE:               {1, variadic},
+	p.Term_NOT:              {1, 1},
+	p.Term_ADD:              {1, variadic},
+	p.Term_SUB:              {1, variadic},
+	p.Term_MUL:              {1, variadic},
+	p.Term_DIV:              {1, variadic},
+	p.Term_MOD:              {2, 2},
+	p.Term_FLOOR:            {1, 1},
+	p.Term_CEIL:             {1, 1},
+	p.Term_ROUND:            {1, 1},
+	p.Term_BIT_AND:          {1, variadic},
+	p.Term_BIT_OR:           {1, variadic},
+	p.Term_BIT_XOR:          {1, variadic},
+	p.Term_BIT_NOT:          {1, 1},
+	p.Term_BIT_SAL:          {2, 2},
+	p.Term_BIT_SAR:          {2, 2},
+	p.Term_APPEND:           {2, 2},
+	p.Term_PREPEND:          {2, 2},
+	p.Term_DIFFERENCE:       {2, 2},
+	p.Term_SET_INSERT:       {2, 2},
+	p.Term_SET_INTERSECTION: {2, 2},
+	p.Term_SET_UNION:        {2, 2},
+	p.Term_SET_DIFFERENCE:   {2, 2},
+	p.Term_KEYS:             {1, 1},
+	p.Term_VALUES:           {1, 1},
+	p.Term_CONCAT_MAP:       {2, 2},
+	p.Term_FOR_EACH:         {2, 2},
+	p.Term_IS_EMPTY:         {1, 1},
+	p.Term_COERCE_TO:        {2, 2},
+	p.Term_TYPE_OF:          {1, 1},
+	p.Term_INFO:             {1, 1},
+	p.Term_MATCH:            {2, 2},
+	p.Term_UPCASE:           {1, 1},
+	p.Term_DOWNCASE:         {1, 1},
+	p.Term_SPLIT:            {1, 3},
+	p.Term_DEFAULT:          {2, 2},
+	p.Term_JSON:             {1, 1},
+	p.Term_TO_JSON_STRING:   {1, 1},
+	p.Term_UUID:             {0, 1},
+	p.Term_ASC:              {1, 1},
+	p.Term_DESC:             {1, 1},
+	p.Term_BRANCH:           {3, variadic},
+	p.Term_FUNCALL:          {1, variadic},
+	p.Term_EQ_JOIN:          {3, 3},
+	p.Term_INNER_JOIN:       {3, 3},
+	p.Term_OUTER_JOIN:       {3, 3},
+	p.Term_ZIP:              {1, 1},
+	p.Term_REDUCE:           {2, 2},
+	p.Term_FOLD:             {3, 3},
+	p.Term_DISTINCT:         {1, 1},
+	p.Term_UNGROUP:          {1, 1},
+	p.Term_CHANGES:          {1, 1},
+	p.Term_INSERT:           {2, 2},
+	p.Term_UPDATE:           {2, 2},
+	p.Term_REPLACE:          {2, 2},
+	p.Term_DELETE:           {1, 1},
+	p.Term_DB_CREATE:        {1, 1},
+	p.Term_DB_DROP:          {1, 1},
+	p.Term_DB_LIST:          {0, 0},
+	p.Term_TABLE_CREATE:     {1, 2},
+	p.Term_TABLE_DROP:       {1, 2},
+	p.Term_TABLE_LIST:       {0, 1},
+	p.Term_INDEX_CREATE:     {2, 3},
+	p.Term_INDEX_DROP:       {2, 2},
+	p.Term_INDEX_LIST:       {1, 1},
+	p.Term_INDEX_RENAME:     {3, 3},
+	p.Term_SYNC:             {1, 1},
+	p.Term_SET_WRITE_HOOK:   {2, 2},
+	p.Term_GET_WRITE_HOOK:   {1, 1},
+	p.Term_NOW:              {0, 0},
+	p.Term_EPOCH_TIME:       {1, 1},
+	p.Term_TO_EPOCH_TIME:    {1, 1},
+	p.Term_ISO8601:          {1, 1},
+	p.Term_TO_ISO8601:       {1, 1},
+	p.Term_IN_TIMEZONE:      {2, 2},
+	p.Term_DURING:           {3, 3},
+	p.Term_DATE:             {1, 1},
+	p.Term_TIME_OF_DAY:      {1, 1},
+	p.Term_TIMEZONE:         {1, 1},
+	p.Term_YEAR:             {1, 1},
+	p.Term_MONTH:            {1, 1},
+	p.Term_DAY:              {1, 1},
+	p.Term_DAY_OF_WEEK:      {1, 1},
+	p.Term_DAY_OF_YEAR:      {1, 1},
+	p.Term_HOURS:            {1, 1},
+	p.Term_MINUTES:          {1, 1},
+	p.Term_SECONDS:          {1, 1},
+	p.Term_TIME:             {4, 7},
+	p.Term_RANGE:            {0, 2},
+	p.Term_RANDOM:           {0, 2},
+	p.Term_MINVAL:           {0, 0},
+	p.Term_MAXVAL:           {0, 0},
+	p.Term_GEOJSON:          {1, 1},
+	p.Term_TO_GEOJSON:       {1, 1},
+	p.Term_POINT:            {2, 2},
+	p.Term_DISTANCE:         {2, 2},
+	p.Term_INTERSECTS:       {2, 2},
+	p.Term_INCLUDES:         {2, 2},
+	p.Term_CIRCLE:           {2, 2},
+	p.Term_GET_INTERSECTING: {2, 2},
+	p.Term_GET_NEAREST:      {2, 2},
+	p.Term_FILL:             {1, 1},
+	p.Term_POLYGON_SUB:      {2, 2},
+}
+
+// termOptArgs contains the names of the optional arguments accepted by each
+// term. Terms which are not listed are not checked.
+var termOptArgs = map[p.Term_TermType][]string{
+	p.Term_TABLE:            {"read_mode", "use_outdated", "identifier_format"},
+	p.Term_GET_ALL:          {"index"},
+	p.Term_BETWEEN:          {"index", "left_bound", "right_bound"},
+	p.Term_FILTER:           {"default"},
+	p.Term_SLICE:            {"left_bound", "right_bound"},
+	p.Term_ORDER_BY:         {"index"},
+	p.Term_UNION:            {"interleave"},
+	p.Term_DISTINCT:         {"index"},
+	p.Term_GROUP:            {"index", "multi"},
+	p.Term_MIN:              {"index"},
+	p.Term_MAX:              {"index"},
+	p.Term_FOLD:             {"emit", "final_emit"},
+	p.Term_EQ_JOIN:          {"index", "ordered"},
+	p.Term_JAVASCRIPT:       {"timeout"},
+	p.Term_HTTP:             {"timeout", "reattempts", "redirects", "verify", "result_format", "method", "auth", "params", "header", "data", "page", "page_limit"},
+	p.Term_RANDOM:           {"float"},
+	p.Term_ISO8601:          {"default_timezone"},
+	p.Term_DURING:           {"left_bound", "right_bound"},
+	p.Term_CIRCLE:           {"num_vertices", "geo_system", "unit", "fill"},
+	p.Term_DISTANCE:         {"geo_system", "unit"},
+	p.Term_GET_INTERSECTING: {"index"},
+	p.Term_GET_NEAREST:      {"index", "max_results", "max_dist", "unit", "geo_system"},
+	p.Term_TABLE_CREATE:     {"primary_key", "durability", "shards", "replicas", "primary_replica_tag", "nonvoting_replica_tags"},
+	p.Term_INDEX_CREATE:     {"multi", "geo"},
+	p.Term_INDEX_RENAME:     {"overwrite"},
+	p.Term_CHANGES:          {"squash", "include_initial", "include_states", "include_offsets", "include_types", "changefeed_queue_size"},
+	p.Term_RECONFIGURE:      {"shards", "replicas", "dry_run", "emergency_repair", "nonvoting_replica_tags", "primary_replica_tag"},
+	p.Term_WAIT:             {"wait_for", "timeout"},
+	p.Term_INSERT:           {"durability", "return_changes", "conflict", "ignore_write_hook"},
+	p.Term_UPDATE:           {"durability", "return_changes", "non_atomic", "conflict", "ignore_write_hook"},
+	p.Term_REPLACE:          {"durability", "return_changes", "non_atomic", "ignore_write_hook"},
+	p.Term_DELETE:           {"durability", "return_changes", "ignore_write_hook"},
+}
+
+// termStringArgs and termNumberArgs contain the positions of arguments which
+// must be strings or numbers, negative positions count from the last argument.
+// Only literal values are checked as the type of other terms is not known
+// until the query is run.
+var termStringArgs = map[p.Term_TermType][]int{
+	p.Term_DB:           {0},
+	p.Term_TABLE:        {-1},
+	p.Term_DB_CREATE:    {0},
+	p.Term_DB_DROP:      {0},
+	p.Term_TABLE_CREATE: {-1},
+	p.Term_TABLE_DROP:   {-1},
+	p.Term_INDEX_CREATE: {1},
+	p.Term_INDEX_DROP:   {1},
+	p.Term_INDEX_RENAME: {1, 2},
+	p.Term_GET_FIELD:    {1},
+	p.Term_COERCE_TO:    {1},
+	p.Term_MATCH:        {1},
+	p.Term_JSON:         {0},
+	p.Term_ISO8601:      {0},
+	p.Term_IN_TIMEZONE:  {1},
+}
+
+var termNumberArgs = map[p.Term_TermType][]int{
+	p.Term_LIMIT:      {1},
+	p.Term_SKIP:       {1},
+	p.Term_NTH:        {1},
+	p.Term_SAMPLE:     {1},
+	p.Term_EPOCH_TIME: {0},
+	p.Term_POINT:      {0, 1},
+}
+
+// Validate checks the query tree for errors which can be detected without
+// sending the query to the server, such as the wrong number of arguments,
+// unknown optional arguments or literal arguments of the wrong type. The
+// checks are based on the term definitions in ql2.proto, terms and arguments
+// which can only be checked by the server are skipped, so a query which
+// passes validation may still fail when run.
+//
+// Errors recorded while the query was being built are also returned.
+func (t Term) Validate() error {
+	if t.lastErr != nil {
+		return t.lastErr
+	}
+	if t.rawQuery || t.isMockAnything {
+		return nil
+	}
+
+	if err := t.validateArgs(); err != nil {
+		return err
+	}
+
+	for _, v := range t.args {
+		if err := v.Validate(); err != nil {
+			return err
+		}
+	}
+	for _, v := range t.optArgs {
+		if err := v.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t Term) validateArgs() error {
+	if err := t.validateOptArgNames(); err != nil {
+		return err
+	}
+
+	// The number and position of arguments spliced with Args is not known
+	for _, v := range t.args {
+		if v.termType == p.Term_ARGS {
+			return nil
+		}
+	}
+
+	if arity, ok := termArity[t.termType]; ok {
+		min, max, n := arity[0], arity[1], len(t.args)
+		if n < min || (max != variadic && n > max) {
+			// Report the number of arguments passed to the Go method
+			if !t.rootTerm {
+				n--
+				min = maxInt(min-1, 0)
+				if max != variadic {
+					max--
+				}
+			}
+
+			return t.validationError(fmt.Sprintf("Expected %s but found %d", describeArity(min, max), n))
+		}
+	}
+
+	// Branch takes test and value pairs followed by an else value
+	if t.termType == p.Term_BRANCH && len(t.args)%2 == 0 {
+		return t.validationError("Cannot call Branch with an even number of arguments")
+	}
+
+	for _, i := range termStringArgs[t.termType] {
+		i = t.argIndex(i)
+		if v, ok := t.datumArg(i); ok && v.Kind() != reflect.String {
+			return t.validationError(fmt.Sprintf("Expected argument %d to be a string but found %s", t.argPosition(i), t.args[i]))
+		}
+	}
+	for _, i := range termNumberArgs[t.termType] {
+		i = t.argIndex(i)
+		if v, ok := t.datumArg(i); ok && !isNumberKind(v.Kind()) {
+			return t.validationError(fmt.Sprintf("Expected argument %d to be a number but found %s", t.argPosition(i), t.args[i]))
+		}
+	}
+
+	return nil
+}
+
+// validateOptArgNames checks the names of the optional arguments of terms
+// listed in termOptArgs.
+func (t Term) validateOptArgNames() error {
+	optArgs, ok := termOptArgs[t.termType]
+	if !ok {
+		return nil
+	}
+
+	for _, k := range sortedTermsObjKeys(t.optArgs) {
+		if !containsString(optArgs, k) {
+			return t.validationError(fmt.Sprintf("Unrecognized optional argument %q, expected one of %s", k, strings.Join(optArgs, ", ")))
+		}
+	}
+
+	return nil
+}
+
+// argIndex resolves negative argument positions from the last argument.
+func (t Term) argIndex(i int) int {
+	if i < 0 {
+		return i + len(t.args)
+	}
+
+	return i
+}
+
+// datumArg returns the value of the argument at index i if it is a non-null
+// literal value.
+func (t Term) datumArg(i int) (reflect.Value, bool) {
+	if i < 0 || i >= len(t.args) || t.args[i].termType != p.Term_DATUM || t.args[i].rawQuery {
+		return reflect.Value{}, false
+	}
+	// The value of a parameter is not known until it is bound
+	if _, ok := t.args[i].data.(queryParam); ok {
+		return reflect.Value{}, false
+	}
+
+	v := reflect.Indirect(reflect.ValueOf(t.args[i].data))
+	return v, v.IsValid()
+}
+
+// argPosition returns the 1-based position of the argument at index i as it
+// was passed to the Go function, the term a method was called on is not
+// counted.
+func (t Term) argPosition(i int) int {
+	if t.rootTerm {
+		return i + 1
+	}
+
+	return i
+}
+
+func (t Term) validationError(msg string) error {
+	name := t.name
+	if name == "" {
+		name = t.termType.String()
+	}
+
+	return RQLDriverError{rqlError(fmt.Sprintf("%s: %s in:\n%s", name, msg, t.String()))}
+}
+
+func describeArity(min, max int) string {
+	plural := func(n int) string {
+		if n == 1 {
+			return "1 argument"
+		}
+		return fmt.Sprintf("%d arguments", n)
+	}
+
+	switch {
+	case max == variadic:
+		return fmt.Sprintf("at least %s", plural(min))
+	case min == max:
+		return plural(min)
+	default:
+		return fmt.Sprintf("between %d and %s", min, plural(max))
+	}
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+
+	return b
+}
+
+func isNumberKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Uintptr, reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+func containsString(s []string, v string) bool {
+	for _, x := range s {
+		if x == v {
+			return true
+		}
+	}
+
+	return false
+}
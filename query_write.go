@@ -4,11 +4,29 @@ import (
 	p "gopkg.in/gorethink/gorethink.v3/ql2"
 )
 
+// Strategies which can be used with InsertOpts.Conflict.
+const (
+	// ConflictError returns an error for documents whose primary key already
+	// exists, this is the default.
+	ConflictError = "error"
+	// ConflictReplace replaces the existing document with the new document.
+	ConflictReplace = "replace"
+	// ConflictUpdate merges the new document into the existing document.
+	ConflictUpdate = "update"
+)
+
 // InsertOpts contains the optional arguments for the Insert term
 type InsertOpts struct {
 	Durability    interface{} `gorethink:"durability,omitempty"`
 	ReturnChanges interface{} `gorethink:"return_changes,omitempty"`
-	Conflict      interface{} `gorethink:"conflict,omitempty"`
+	// Conflict is either one of ConflictError, ConflictReplace or
+	// ConflictUpdate, or a function of the form
+	// func(id, oldDoc, newDoc r.Term) interface{} which returns the document
+	// to store.
+	Conflict interface{} `gorethink:"conflict,omitempty"`
+	// IgnoreWriteHook skips the write hook of the table, it requires the
+	// config permission and RethinkDB 2.4 or later.
+	IgnoreWriteHook interface{} `gorethink:"ignore_write_hook,omitempty"`
 }
 
 func (o InsertOpts) toMap() map[string]interface{} {
@@ -17,6 +35,17 @@ func (o InsertOpts) toMap() map[string]interface{} {
 
 // Insert documents into a table. Accepts a single document or an array
 // of documents.
+//
+// Conflicts with existing documents can be resolved by a function, for
+// example to count how many times a document was inserted:
+//
+//     r.Table("visits").Insert(visit, r.InsertOpts{
+//         Conflict: func(id, oldDoc, newDoc r.Term) interface{} {
+//             return newDoc.Merge(map[string]interface{}{
+//                 "count": oldDoc.Field("count").Add(1),
+//             })
+//         },
+//     })
 func (t Term) Insert(arg interface{}, optArgs ...InsertOpts) Term {
 	opts := map[string]interface{}{}
 	if len(optArgs) >= 1 {
@@ -31,6 +60,9 @@ type UpdateOpts struct {
 	ReturnChanges interface{} `gorethink:"return_changes,omitempty"`
 	NonAtomic     interface{} `gorethink:"non_atomic,omitempty"`
 	Conflict      interface{} `gorethink:"conflict,omitempty"`
+	// IgnoreWriteHook skips the write hook of the table, it requires the
+	// config permission and RethinkDB 2.4 or later.
+	IgnoreWriteHook interface{} `gorethink:"ignore_write_hook,omitempty"`
 }
 
 func (o UpdateOpts) toMap() map[string]interface{} {
@@ -45,7 +77,11 @@ func (t Term) Update(arg interface{}, optArgs ...UpdateOpts) Term {
 	if len(optArgs) >= 1 {
 		opts = optArgs[0].toMap()
 	}
-	return constructMethodTerm(t, "Update", p.Term_UPDATE, []interface{}{funcWrap(arg)}, opts)
+	term := constructMethodTerm(t, "Update", p.Term_UPDATE, []interface{}{funcWrap(arg)}, opts)
+	if err := checkFuncArg("Update", arg, 1); err != nil {
+		term.lastErr = err
+	}
+	return term
 }
 
 // ReplaceOpts contains the optional arguments for the Replace term
@@ -53,6 +89,9 @@ type ReplaceOpts struct {
 	Durability    interface{} `gorethink:"durability,omitempty"`
 	ReturnChanges interface{} `gorethink:"return_changes,omitempty"`
 	NonAtomic     interface{} `gorethink:"non_atomic,omitempty"`
+	// IgnoreWriteHook skips the write hook of the table, it requires the
+	// config permission and RethinkDB 2.4 or later.
+	IgnoreWriteHook interface{} `gorethink:"ignore_write_hook,omitempty"`
 }
 
 func (o ReplaceOpts) toMap() map[string]interface{} {
@@ -67,13 +106,20 @@ func (t Term) Replace(arg interface{}, optArgs ...ReplaceOpts) Term {
 	if len(optArgs) >= 1 {
 		opts = optArgs[0].toMap()
 	}
-	return constructMethodTerm(t, "Replace", p.Term_REPLACE, []interface{}{funcWrap(arg)}, opts)
+	term := constructMethodTerm(t, "Replace", p.Term_REPLACE, []interface{}{funcWrap(arg)}, opts)
+	if err := checkFuncArg("Replace", arg, 1); err != nil {
+		term.lastErr = err
+	}
+	return term
 }
 
 // DeleteOpts contains the optional arguments for the Delete term
 type DeleteOpts struct {
 	Durability    interface{} `gorethink:"durability,omitempty"`
 	ReturnChanges interface{} `gorethink:"return_changes,omitempty"`
+	// IgnoreWriteHook skips the write hook of the table, it requires the
+	// config permission and RethinkDB 2.4 or later.
+	IgnoreWriteHook interface{} `gorethink:"ignore_write_hook,omitempty"`
 }
 
 func (o DeleteOpts) toMap() map[string]interface{} {
@@ -0,0 +1,151 @@
+package gorethink
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/cenkalti/backoff"
+)
+
+// ReconnectOpts configures automatic reconnection of a session, see
+// ConnectOpts.Reconnect. The time waited between attempts grows exponentially
+// from InitialInterval up to MaxInterval and is randomized by up to
+// RandomizationFactor so that many clients do not reconnect at the same time.
+type ReconnectOpts struct {
+	// InitialInterval is the time waited before the first attempt, by default
+	// 500ms.
+	InitialInterval time.Duration
+	// MaxInterval is the maximum time waited between attempts, by default 60s.
+	MaxInterval time.Duration
+	// Multiplier is the factor the wait is increased by after each failed
+	// attempt, by default 1.5.
+	Multiplier float64
+	// RandomizationFactor is the jitter applied to each wait, a value of 0.5
+	// randomizes the wait by up to 50% either way. By default this is 0.5.
+	RandomizationFactor float64
+	// MaxElapsedTime is the time after which the session stops trying to
+	// reconnect, by default the session keeps trying until it is closed. If
+	// reconnecting stops the next query which fails to connect starts
+	// reconnecting again.
+	MaxElapsedTime time.Duration
+	// OnAttempt, if set, is called after each reconnection attempt.
+	OnAttempt func(ReconnectEvent)
+}
+
+// ReconnectEvent describes an attempt by a session to reconnect.
+type ReconnectEvent struct {
+	// Attempt is the number of the attempt, starting from 1.
+	Attempt int
+	// Err is the error returned by the attempt, nil if the session
+	// reconnected.
+	Err error
+}
+
+func (o *ReconnectOpts) backOff() backoff.BackOff {
+	b := backoff.NewExponentialBackOff()
+	if o.InitialInterval > 0 {
+		b.InitialInterval = o.InitialInterval
+	}
+	if o.MaxInterval > 0 {
+		b.MaxInterval = o.MaxInterval
+	}
+	if o.Multiplier > 0 {
+		b.Multiplier = o.Multiplier
+	}
+	if o.RandomizationFactor > 0 {
+		b.RandomizationFactor = o.RandomizationFactor
+	}
+	b.MaxElapsedTime = o.MaxElapsedTime
+	b.Reset()
+
+	return b
+}
+
+// reconnectOnError starts reconnecting the session in the background if
+// automatic reconnection is enabled and err shows the session has lost its
+// connection to the database.
+func (s *Session) reconnectOnError(err error) {
+	if s.opts.Reconnect == nil {
+		return
+	}
+	if !isConnectionError(err) && err != ErrNoConnections {
+		return
+	}
+
+	if atomic.CompareAndSwapInt32(&s.reconnecting, 0, 1) {
+		go s.reconnectLoop(s.opts.Reconnect)
+	}
+}
+
+// reconnectLoop attempts to reconnect the session, waiting between attempts,
+// until it succeeds, the session is closed or MaxElapsedTime is exceeded.
+func (s *Session) reconnectLoop(opts *ReconnectOpts) {
+	defer atomic.StoreInt32(&s.reconnecting, 0)
+
+	b := opts.backOff()
+	for attempt := 1; ; attempt++ {
+		wait := b.NextBackOff()
+		if wait == backoff.Stop {
+			Log.Warnf("Stopped reconnecting after %d attempts", attempt-1)
+			return
+		}
+		time.Sleep(wait)
+
+		s.mu.RLock()
+		closed := s.closed
+		s.mu.RUnlock()
+		if closed {
+			return
+		}
+
+		err := s.reconnectAttempt()
+		if err != nil {
+			Log.Debugf("Error reconnecting: %s", err)
+		}
+		if opts.OnAttempt != nil {
+			opts.OnAttempt(ReconnectEvent{
+				Attempt: attempt,
+				Err:     err,
+			})
+		}
+		if err == nil {
+			return
+		}
+	}
+}
+
+// reconnectAttempt checks whether the session's cluster can be used to run
+// queries, if it cannot then the cluster is replaced by a newly connected one.
+func (s *Session) reconnectAttempt() error {
+	s.mu.RLock()
+	cluster := s.cluster
+	hosts := s.hosts
+	s.mu.RUnlock()
+
+	if cluster != nil {
+		if _, err := cluster.ServerInfo(); err == nil {
+			return nil
+		}
+	}
+
+	cluster, err := NewCluster(hosts, s.opts)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		cluster.Close()
+		return nil
+	}
+	old := s.cluster
+	s.cluster = cluster
+	s.mu.Unlock()
+
+	if old != nil {
+		old.Close()
+	}
+
+	return nil
+}
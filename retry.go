@@ -0,0 +1,131 @@
+package gorethink
+
+import (
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// RetryPolicy controls how queries are retried when they fail. A RetryPolicy
+// can be set for all queries run by a session using ConnectOpts.RetryPolicy or
+// for a single query using RunOpts.RetryPolicy and ExecOpts.RetryPolicy.
+//
+// Queries may be executed more than once when retried. By default queries are
+// only retried on errors which occur before the query could have been run,
+// see IsTransientError. Retrying on other errors, for example by using
+// IsIndeterminateError, should only be used for idempotent queries as a write
+// which is not idempotent, such as an Insert without a primary key or an
+// Update which increments a field, may be applied twice.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of times a query is attempted,
+	// including the first attempt. By default ConnectOpts.NumRetries is used.
+	MaxAttempts int
+	// Backoff returns the time to wait before retrying the query, attempt is
+	// the number of the retry starting from 1. By default queries are retried
+	// immediately.
+	Backoff func(attempt int) time.Duration
+	// Retryable returns true if a query which failed with err should be
+	// retried. By default IsTransientError is used, to also retry queries
+	// which may have been applied use IsIndeterminateError.
+	Retryable func(err error) bool
+}
+
+// ExponentialBackoff returns a RetryPolicy backoff function which waits
+// initial before the first retry, doubling the wait for each subsequent retry
+// up to max.
+func ExponentialBackoff(initial, max time.Duration) func(attempt int) time.Duration {
+	return func(attempt int) time.Duration {
+		wait := initial
+		for i := 1; i < attempt && wait < max; i++ {
+			wait *= 2
+		}
+		if wait > max {
+			wait = max
+		}
+
+		return wait
+	}
+}
+
+// IsTransientError returns true if err may not occur if the query is retried
+// and the query was not applied, this includes connection errors, timeouts
+// waiting for a connection and ReQL availability errors. It is used by
+// RetryPolicy when Retryable is not set.
+func IsTransientError(err error) bool {
+	if isConnectionError(err) {
+		return true
+	}
+
+	if _, ok := err.(RQLAvailabilityError); ok {
+		return true
+	}
+
+	return err == ErrNoConnections
+}
+
+// IsIndeterminateError returns true if err is a transient error, see
+// IsTransientError, or if the query failed in a way that it may or may not
+// have been applied, such as a query timeout or a ReQL operation error. It can
+// be used as RetryPolicy.Retryable but may result in writes which are not
+// idempotent being applied more than once.
+func IsIndeterminateError(err error) bool {
+	if IsTransientError(err) {
+		return true
+	}
+
+	switch err.(type) {
+	case RQLOpFailedError, RQLOpIndeterminateError:
+		return true
+	}
+
+	return err == ErrQueryTimeout
+}
+
+// retryPolicy returns the retry policy used for the query, nil if the query
+// is only retried on connection errors.
+func (c *Cluster) retryPolicy(q Query) *RetryPolicy {
+	if q.retryPolicy != nil {
+		return q.retryPolicy
+	}
+
+	return c.opts.RetryPolicy
+}
+
+// maxAttempts returns the maximum number of times a query is attempted.
+func (c *Cluster) maxAttempts(policy *RetryPolicy) int {
+	if policy != nil && policy.MaxAttempts > 0 {
+		return policy.MaxAttempts
+	}
+
+	return c.numRetries()
+}
+
+// waitRetry waits before the query is retried, returning false if ctx is done
+// before then.
+func waitRetry(ctx context.Context, policy *RetryPolicy, attempt int) bool {
+	if ctx != nil && ctx.Err() != nil {
+		return false
+	}
+	if policy == nil || policy.Backoff == nil {
+		return true
+	}
+
+	wait := policy.Backoff(attempt)
+	if wait <= 0 {
+		return true
+	}
+	if ctx == nil {
+		time.Sleep(wait)
+		return true
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
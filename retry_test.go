@@ -0,0 +1,64 @@
+package gorethink
+
+import (
+	"errors"
+	"time"
+
+	test "gopkg.in/check.v1"
+)
+
+func (s *RethinkSuite) TestRetryExponentialBackoff(c *test.C) {
+	backoff := ExponentialBackoff(100*time.Millisecond, time.Second)
+
+	c.Assert(backoff(1), test.Equals, 100*time.Millisecond)
+	c.Assert(backoff(2), test.Equals, 200*time.Millisecond)
+	c.Assert(backoff(4), test.Equals, 800*time.Millisecond)
+	c.Assert(backoff(5), test.Equals, time.Second)
+	c.Assert(backoff(100), test.Equals, time.Second)
+}
+
+func (s *RethinkSuite) TestRetryIsTransientError(c *test.C) {
+	c.Assert(IsTransientError(RQLConnectionError{rqlError("connection reset")}), test.Equals, true)
+	c.Assert(IsTransientError(ErrConnectionClosed), test.Equals, true)
+	c.Assert(IsTransientError(ErrNoConnections), test.Equals, true)
+	c.Assert(IsTransientError(RQLAvailabilityError{}), test.Equals, true)
+
+	// Queries which may have been applied are not retried by default
+	c.Assert(IsTransientError(ErrQueryTimeout), test.Equals, false)
+	c.Assert(IsTransientError(RQLOpFailedError{}), test.Equals, false)
+	c.Assert(IsTransientError(RQLOpIndeterminateError{}), test.Equals, false)
+
+	c.Assert(IsTransientError(nil), test.Equals, false)
+	c.Assert(IsTransientError(RQLQueryLogicError{}), test.Equals, false)
+	c.Assert(IsTransientError(errors.New("error")), test.Equals, false)
+}
+
+func (s *RethinkSuite) TestRetryIsIndeterminateError(c *test.C) {
+	c.Assert(IsIndeterminateError(ErrConnectionClosed), test.Equals, true)
+	c.Assert(IsIndeterminateError(RQLAvailabilityError{}), test.Equals, true)
+	c.Assert(IsIndeterminateError(ErrQueryTimeout), test.Equals, true)
+	c.Assert(IsIndeterminateError(RQLOpFailedError{}), test.Equals, true)
+	c.Assert(IsIndeterminateError(RQLOpIndeterminateError{}), test.Equals, true)
+
+	c.Assert(IsIndeterminateError(nil), test.Equals, false)
+	c.Assert(IsIndeterminateError(RQLQueryLogicError{}), test.Equals, false)
+}
+
+func (s *RethinkSuite) TestRetryShouldRetryQuery(c *test.C) {
+	q := Query{}
+
+	// Without a policy only connection errors are retried
+	c.Assert(shouldRetryQuery(q, ErrConnectionClosed, nil), test.Equals, true)
+	c.Assert(shouldRetryQuery(q, RQLOpFailedError{}, nil), test.Equals, false)
+
+	policy := &RetryPolicy{}
+	c.Assert(shouldRetryQuery(q, nil, policy), test.Equals, false)
+	c.Assert(shouldRetryQuery(q, RQLAvailabilityError{}, policy), test.Equals, true)
+	c.Assert(shouldRetryQuery(q, RQLOpIndeterminateError{}, policy), test.Equals, false)
+
+	policy.Retryable = func(err error) bool {
+		return err == ErrQueryTimeout
+	}
+	c.Assert(shouldRetryQuery(q, ErrQueryTimeout, policy), test.Equals, true)
+	c.Assert(shouldRetryQuery(q, ErrConnectionClosed, policy), test.Equals, false)
+}
@@ -1,10 +1,21 @@
 package gorethink
 
 import (
+	"bufio"
+	"crypto/tls"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
 	"os"
+	"runtime"
+	"sync"
 	"time"
 
+	"github.com/cenkalti/backoff"
+	"golang.org/x/net/context"
 	test "gopkg.in/check.v1"
+	p "gopkg.in/gorethink/gorethink.v3/ql2"
 )
 
 func (s *RethinkSuite) TestSessionConnect(c *test.C) {
@@ -54,6 +65,87 @@ func (s *RethinkSuite) TestSessionConnectHandshakeV0_4(c *test.C) {
 	c.Assert(response, test.Equals, "Hello World")
 }
 
+func (s *RethinkSuite) TestSessionConnectMaxOpen(c *test.C) {
+	session, err := Connect(ConnectOpts{
+		Address: url,
+		MaxOpen: 1,
+	})
+	c.Assert(err, test.IsNil)
+	defer session.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			var response int
+			err := Expr(i).ReadOne(&response, session)
+			c.Check(err, test.IsNil)
+			c.Check(response, test.Equals, i)
+		}(i)
+	}
+	wg.Wait()
+}
+
+func (s *RethinkSuite) TestSessionConnectMaxOpenTimeout(c *test.C) {
+	session, err := Connect(ConnectOpts{
+		Address: url,
+		MaxOpen: 1,
+	})
+	c.Assert(err, test.IsNil)
+	defer session.Close()
+
+	// Hold the only connection by leaving a partial cursor open
+	res, err := Range(1000).Run(session, RunOpts{MaxBatchRows: 10})
+	c.Assert(err, test.IsNil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = Expr(1).Run(session, RunOpts{Context: ctx})
+	c.Assert(err, test.Equals, ErrQueryTimeout)
+
+	// Once released the connection is reused
+	c.Assert(res.Close(), test.IsNil)
+	var response int
+	err = Expr(1).ReadOne(&response, session)
+	c.Assert(err, test.IsNil)
+}
+
+func (s *RethinkSuite) TestSessionStats(c *test.C) {
+	session, err := Connect(ConnectOpts{
+		Address: url,
+		MaxOpen: 1,
+	})
+	c.Assert(err, test.IsNil)
+	defer session.Close()
+
+	res, err := Range(1000).Run(session, RunOpts{MaxBatchRows: 10})
+	c.Assert(err, test.IsNil)
+
+	stats := session.Stats()
+	c.Assert(stats.MaxOpenConnections, test.Equals, 1)
+	c.Assert(stats.OpenConnections, test.Equals, 1)
+	c.Assert(stats.InUse, test.Equals, 1)
+	c.Assert(stats.Idle, test.Equals, 0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = Expr(1).Run(session, RunOpts{Context: ctx})
+	c.Assert(err, test.Equals, ErrQueryTimeout)
+
+	c.Assert(res.Close(), test.IsNil)
+
+	stats = session.Stats()
+	c.Assert(stats.InUse, test.Equals, 0)
+	c.Assert(stats.Idle, test.Equals, 1)
+	c.Assert(stats.WaitCount, test.Equals, int64(1))
+	c.Assert(stats.WaitDuration > 0, test.Equals, true)
+
+	session.Close()
+	c.Assert(session.Stats(), test.Equals, PoolStats{})
+}
+
 func (s *RethinkSuite) TestSessionReconnect(c *test.C) {
 	session, err := Connect(ConnectOpts{
 		Address: url,
@@ -119,6 +211,28 @@ func (s *RethinkSuite) TestSessionServer(c *test.C) {
 	c.Assert(len(server.Name) > 0, test.Equals, true)
 }
 
+func (s *RethinkSuite) TestSessionServerInfo(c *test.C) {
+	session, err := Connect(ConnectOpts{
+		Address: url,
+	})
+	c.Assert(err, test.IsNil)
+	defer session.Close()
+
+	info, err := session.ServerInfo()
+	c.Assert(err, test.IsNil)
+	c.Assert(len(info.ID) > 0, test.Equals, true)
+	c.Assert(len(info.Name) > 0, test.Equals, true)
+	c.Assert(info.Proxy, test.Equals, false)
+
+	server, err := session.Server()
+	c.Assert(err, test.IsNil)
+	c.Assert(server.ID, test.Equals, info.ID)
+
+	session.Close()
+	_, err = session.ServerInfo()
+	c.Assert(err, test.Equals, ErrConnectionClosed)
+}
+
 func (s *RethinkSuite) TestSessionConnectDatabase(c *test.C) {
 	session, err := Connect(ConnectOpts{
 		Address:  url,
@@ -136,24 +250,699 @@ func (s *RethinkSuite) TestSessionConnectDatabase(c *test.C) {
 	c.Assert(session.Database(), test.Equals, "test3")
 }
 
+func (s *RethinkSuite) TestSessionUseConcurrent(c *test.C) {
+	session, err := Connect(ConnectOpts{
+		Address: url,
+	})
+	c.Assert(err, test.IsNil)
+	defer session.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			if i%2 == 0 {
+				session.Use("test")
+			} else {
+				session.Use("test2")
+			}
+
+			var response int
+			err := Expr(i).ReadOne(&response, session)
+			c.Check(err, test.IsNil)
+			c.Check(response, test.Equals, i)
+		}(i)
+	}
+	wg.Wait()
+}
+
+func (s *RethinkSuite) TestSessionUseRunOptsDB(c *test.C) {
+	session, err := Connect(ConnectOpts{
+		Address:  url,
+		Database: "test2",
+	})
+	c.Assert(err, test.IsNil)
+	defer session.Close()
+
+	// The database passed to Run takes precedence over the default
+	var response []interface{}
+	err = TableList().ReadAll(&response, session, RunOpts{DB: "test"})
+	c.Assert(err, test.IsNil)
+}
+
 func (s *RethinkSuite) TestSessionConnectUsername(c *test.C) {
 	session, err := Connect(ConnectOpts{
 		Address: url,
 	})
 	c.Assert(err, test.IsNil)
+	defer session.Close()
 
 	DB("rethinkdb").Table("users").Insert(map[string]string{
 		"id":       "gorethink_test",
 		"password": "password",
 	}).Exec(session)
 
-	session, err = Connect(ConnectOpts{
+	userSession, err := Connect(ConnectOpts{
 		Address:  url,
 		Username: "gorethink_test",
 		Password: "password",
 	})
 	c.Assert(err, test.IsNil)
+	defer userSession.Close()
+
+	_, err = Expr("Hello World").Run(userSession)
+	c.Assert(err, test.IsNil)
+}
+
+func (s *RethinkSuite) TestSessionConnectUsernameEscaped(c *test.C) {
+	session, err := Connect(ConnectOpts{
+		Address: url,
+	})
+	c.Assert(err, test.IsNil)
+	defer session.Close()
+
+	DB("rethinkdb").Table("users").Insert(map[string]string{
+		"id":       "gorethink,test=escaped",
+		"password": "password",
+	}).Exec(session)
+
+	userSession, err := Connect(ConnectOpts{
+		Address:  url,
+		Username: "gorethink,test=escaped",
+		Password: "password",
+	})
+	c.Assert(err, test.IsNil)
+	defer userSession.Close()
+
+	_, err = Expr("Hello World").Run(userSession)
+	c.Assert(err, test.IsNil)
+}
+
+func (s *RethinkSuite) TestSessionConnectDialFunc(c *test.C) {
+	var dialed []string
+	var mu sync.Mutex
+
+	session, err := Connect(ConnectOpts{
+		Address: url,
+		DialFunc: func(network, address string) (net.Conn, error) {
+			mu.Lock()
+			dialed = append(dialed, address)
+			mu.Unlock()
+
+			return net.Dial(network, address)
+		},
+	})
+	c.Assert(err, test.IsNil)
+	defer session.Close()
+
+	_, err = Expr("Hello World").Run(session)
+	c.Assert(err, test.IsNil)
+
+	mu.Lock()
+	defer mu.Unlock()
+	c.Assert(len(dialed) > 0, test.Equals, true)
+	c.Assert(dialed[0], test.Equals, url)
+}
+
+func (s *RethinkSuite) TestSessionConnectBufferSizes(c *test.C) {
+	session, err := Connect(ConnectOpts{
+		Address:         url,
+		ReadBufferSize:  16,
+		WriteBufferSize: 16,
+	})
+	c.Assert(err, test.IsNil)
+	defer session.Close()
+
+	// Both the query and response are larger than the buffers
+	var response []int
+	err = Expr(make([]int, 1000)).Map(func(row Term) Term {
+		return row.Add(1)
+	}).ReadAll(&response, session)
+	c.Assert(err, test.IsNil)
+	c.Assert(response, test.HasLen, 1000)
+	c.Assert(response[999], test.Equals, 1)
+}
+
+func (s *RethinkSuite) TestSessionConnectLazy(c *test.C) {
+	session, err := Connect(ConnectOpts{
+		Address: url,
+		Lazy:    true,
+	})
+	c.Assert(err, test.IsNil)
+	defer session.Close()
+
+	c.Assert(session.Stats().OpenConnections, test.Equals, 0)
+
+	var response string
+	err = Expr("Hello World").ReadOne(&response, session)
+	c.Assert(err, test.IsNil)
+	c.Assert(response, test.Equals, "Hello World")
+}
+
+func (s *RethinkSuite) TestSessionConnectLazyUnreachable(c *test.C) {
+	// Find an address which nothing is listening on
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, test.IsNil)
+	address := ln.Addr().String()
+	ln.Close()
+
+	session, err := Connect(ConnectOpts{
+		Address: address,
+		Lazy:    true,
+	})
+	c.Assert(err, test.IsNil)
+	defer session.Close()
+
+	err = Expr("Hello World").Exec(session)
+	c.Assert(err, test.NotNil)
+}
+
+func (s *RethinkSuite) TestPoolConnContextCanceled(c *test.C) {
+	// The only connection is held by a dial which does not complete
+	release := make(chan struct{})
+	defer close(release)
+	pool, err := NewPool(NewHost("127.0.0.1", 1), &ConnectOpts{
+		MaxOpen: 1,
+		DialFunc: func(network, address string) (net.Conn, error) {
+			<-release
+			return nil, errors.New("connection refused")
+		},
+	})
+	c.Assert(err, test.IsNil)
+	defer pool.Close()
+
+	go pool.conn(nil)
+	for deadline := time.Now().Add(time.Second); pool.Stats().Dialing < 1; {
+		c.Assert(time.Now().Before(deadline), test.Equals, true)
+		time.Sleep(time.Millisecond)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = pool.conn(ctx)
+	c.Assert(err, test.Equals, context.Canceled)
+
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = pool.conn(ctx)
+	c.Assert(err, test.Equals, ErrQueryTimeout)
+}
+
+func (s *RethinkSuite) TestPoolDialErrorWaiters(c *test.C) {
+	// Connections fail once the test releases the dial
+	release := make(chan struct{})
+	pool, err := NewPool(NewHost("127.0.0.1", 1), &ConnectOpts{
+		MaxOpen: 1,
+		DialFunc: func(network, address string) (net.Conn, error) {
+			<-release
+			return nil, errors.New("connection refused")
+		},
+	})
+	c.Assert(err, test.IsNil)
+	defer pool.Close()
+
+	errs := make(chan error, 3)
+	for i := 0; i < 3; i++ {
+		go func() {
+			_, err := pool.conn(nil)
+			errs <- err
+		}()
+	}
+
+	for deadline := time.Now().Add(time.Second); pool.Stats().WaitCount < 2; {
+		c.Assert(time.Now().Before(deadline), test.Equals, true)
+		time.Sleep(time.Millisecond)
+	}
+	stats := pool.Stats()
+	c.Assert(stats.Dialing, test.Equals, 1)
+	c.Assert(stats.InUse, test.Equals, 0)
+	close(release)
+
+	for i := 0; i < 3; i++ {
+		select {
+		case err := <-errs:
+			c.Assert(err, test.NotNil)
+		case <-time.After(time.Second):
+			c.Fatalf("waiter %d hung; stats %+v", i, pool.Stats())
+		}
+	}
+	c.Assert(pool.Stats().OpenConnections, test.Equals, 0)
+	c.Assert(pool.Stats().Dialing, test.Equals, 0)
+}
+
+func (s *RethinkSuite) TestSessionMaxConcurrentQueries(c *test.C) {
+	session, err := Connect(ConnectOpts{
+		Address:              url,
+		MaxConcurrentQueries: 1,
+	})
+	c.Assert(err, test.IsNil)
+	defer session.Close()
+
+	// Run a slow query in the background
+	done := make(chan error, 1)
+	go func() {
+		done <- JS("while(true) {}", JSOpts{Timeout: 5}).Exec(session, ExecOpts{
+			Timeout: 500 * time.Millisecond,
+		})
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	err = Expr("Hello World").Exec(session)
+	c.Assert(err, test.Equals, ErrTooManyQueries)
+
+	err = Expr("Hello World").Exec(session, ExecOpts{
+		Timeout: 10 * time.Millisecond,
+	})
+	c.Assert(err, test.Equals, ErrTooManyQueries)
+
+	// Queries with a context wait for the running query to finish
+	err = Expr("Hello World").Exec(session, ExecOpts{
+		Timeout: 5 * time.Second,
+	})
+	c.Assert(err, test.IsNil)
+	c.Assert(<-done, test.Equals, ErrQueryTimeout)
+}
+
+func (s *RethinkSuite) TestSessionConnectHandshakeTimeout(c *test.C) {
+	// Accept connections but never respond to the handshake
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, test.IsNil)
+	defer ln.Close()
+
+	var mu sync.Mutex
+	var conns []net.Conn
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+
+			mu.Lock()
+			conns = append(conns, conn)
+			mu.Unlock()
+		}
+	}()
+
+	start := time.Now()
+	_, err = Connect(ConnectOpts{
+		Address:          ln.Addr().String(),
+		HandshakeTimeout: 100 * time.Millisecond,
+	})
+	c.Assert(err, test.NotNil)
+	c.Assert(time.Since(start) < 5*time.Second, test.Equals, true)
+
+	ln.Close()
+	mu.Lock()
+	for _, conn := range conns {
+		conn.Close()
+	}
+	mu.Unlock()
+}
+
+func (s *RethinkSuite) TestSessionConnectResolveAddresses(c *test.C) {
+	conn := &Connection{address: "localhost:28015", opts: &ConnectOpts{}}
+	addresses := conn.resolveAddresses("tcp")
+	c.Assert(len(addresses) > 0, test.Equals, true)
+	for _, address := range addresses {
+		host, port, err := net.SplitHostPort(address)
+		c.Assert(err, test.IsNil)
+		c.Assert(net.ParseIP(host), test.NotNil)
+		c.Assert(port, test.Equals, "28015")
+	}
+
+	conn = &Connection{address: "127.0.0.1:28015", opts: &ConnectOpts{}}
+	c.Assert(conn.resolveAddresses("tcp"), test.DeepEquals, []string{"127.0.0.1:28015"})
+
+	conn = &Connection{address: "/var/run/rethinkdb.sock", opts: &ConnectOpts{}}
+	c.Assert(conn.resolveAddresses("unix"), test.DeepEquals, []string{"/var/run/rethinkdb.sock"})
+}
+
+func (s *RethinkSuite) TestConnectionTLSServerName(c *test.C) {
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+
+	// The server records the name sent by the client and aborts the handshake
+	serverNames := make(chan string, 1)
+	go tls.Server(server, &tls.Config{
+		GetCertificate: func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+			serverNames <- hello.ServerName
+			return nil, errors.New("no certificate")
+		},
+	}).Handshake()
+
+	tlsConfig := &tls.Config{}
+	conn := &Connection{address: "db.example.com:28015", opts: &ConnectOpts{
+		TLSConfig: tlsConfig,
+		DialFunc: func(network, address string) (net.Conn, error) {
+			return client, nil
+		},
+	}}
+	_, err := conn.dial()
+	c.Assert(err, test.NotNil)
+	c.Assert(<-serverNames, test.Equals, "db.example.com")
+	c.Assert(tlsConfig.ServerName, test.Equals, "")
+}
+
+type closeRecorderConn struct {
+	net.Conn
+	once   sync.Once
+	closed chan struct{}
+}
+
+func (c *closeRecorderConn) Close() error {
+	c.once.Do(func() { close(c.closed) })
+	return c.Conn.Close()
+}
+
+func (s *RethinkSuite) TestConnectionHandshakeErrorClosesConn(c *test.C) {
+	// The server closes the connection before the handshake is sent
+	client, server := net.Pipe()
+	server.Close()
+
+	conn := &closeRecorderConn{Conn: client, closed: make(chan struct{})}
+	_, err := newConnection("db.example.com:28015", &ConnectOpts{
+		DialFunc: func(network, address string) (net.Conn, error) {
+			return conn, nil
+		},
+	}, HandshakeV0_4)
+	c.Assert(err, test.NotNil)
+
+	select {
+	case <-conn.closed:
+	default:
+		c.Fatal("connection was not closed")
+	}
+}
+
+func (s *RethinkSuite) TestConnectionReadTimeoutPending(c *test.C) {
+	// The server reads queries but never responds
+	client, server := net.Pipe()
+	defer client.Close()
+	go io.Copy(ioutil.Discard, server)
+
+	conn := &Connection{
+		Conn:    client,
+		opts:    &ConnectOpts{ReadTimeout: 20 * time.Millisecond},
+		writer:  bufio.NewWriter(client),
+		cursors: make(map[int64]*Cursor),
+		pending: make(map[int64][]chan *Response),
+	}
+
+	_, _, err := conn.Query(nil, Query{Type: p.Query_START, Opts: map[string]interface{}{}})
+	c.Assert(err, test.Equals, ErrQueryTimeout)
+
+	conn.mu.Lock()
+	c.Assert(conn.pending, test.HasLen, 0)
+	conn.mu.Unlock()
+}
+
+func (s *RethinkSuite) TestConnectionReadTimeoutGoroutines(c *test.C) {
+	// The server reads queries but never responds
+	client, server := net.Pipe()
+	defer client.Close()
+	go io.Copy(ioutil.Discard, server)
+
+	conn := &Connection{
+		Conn:    client,
+		opts:    &ConnectOpts{ReadTimeout: 10 * time.Millisecond},
+		writer:  bufio.NewWriter(client),
+		cursors: make(map[int64]*Cursor),
+		pending: make(map[int64][]chan *Response),
+	}
+
+	before := runtime.NumGoroutine()
+	for i := 0; i < 5; i++ {
+		_, _, err := conn.Query(nil, Query{Type: p.Query_START, Token: int64(i), Opts: map[string]interface{}{}})
+		c.Assert(err, test.Equals, ErrQueryTimeout)
+	}
+
+	// The goroutines waiting for the responses exit once the queries time out
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before {
+		if time.Now().After(deadline) {
+			c.Fatalf("%d goroutines are still running", runtime.NumGoroutine()-before)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func (s *RethinkSuite) TestSessionConnectUnixDiscoverHosts(c *test.C) {
+	_, err := Connect(ConnectOpts{
+		Address:       "/var/run/rethinkdb.sock",
+		Network:       "unix",
+		DiscoverHosts: true,
+	})
+	c.Assert(err, test.Equals, ErrUnixDiscoverHosts)
+}
+
+func (s *RethinkSuite) TestSessionPingInterval(c *test.C) {
+	session, err := Connect(ConnectOpts{
+		Address:      url,
+		PingInterval: 100 * time.Millisecond,
+	})
+	c.Assert(err, test.IsNil)
+	defer session.Close()
+
+	_, err = Expr("Hello World").Run(session)
+	c.Assert(err, test.IsNil)
+
+	// Wait for the idle connection to be pinged
+	time.Sleep(300 * time.Millisecond)
+
+	stats := session.Stats()
+	c.Assert(stats.OpenConnections, test.Equals, 1)
+	c.Assert(stats.ErrorClosed, test.Equals, int64(0))
 
 	_, err = Expr("Hello World").Run(session)
 	c.Assert(err, test.IsNil)
 }
+
+func (s *RethinkSuite) TestSessionPingIdleAfter(c *test.C) {
+	session, err := Connect(ConnectOpts{
+		Address:       url,
+		PingIdleAfter: 10 * time.Millisecond,
+	})
+	c.Assert(err, test.IsNil)
+	defer session.Close()
+
+	err = Expr("Hello World").Exec(session)
+	c.Assert(err, test.IsNil)
+
+	time.Sleep(50 * time.Millisecond)
+
+	// The idle connection should be checked and reused
+	err = Expr("Hello World").Exec(session)
+	c.Assert(err, test.IsNil)
+
+	stats := session.Stats()
+	c.Assert(stats.OpenConnections, test.Equals, 1)
+	c.Assert(stats.ErrorClosed, test.Equals, int64(0))
+}
+
+func (s *RethinkSuite) TestSessionIdlePruning(c *test.C) {
+	session, err := Connect(ConnectOpts{
+		Address:           url,
+		InitialCap:        4,
+		MaxIdle:           4,
+		MinIdle:           1,
+		IdleTimeout:       10 * time.Millisecond,
+		IdleCheckInterval: 10 * time.Millisecond,
+	})
+	c.Assert(err, test.IsNil)
+	defer session.Close()
+
+	c.Assert(session.Stats().Idle, test.Equals, 4)
+
+	time.Sleep(100 * time.Millisecond)
+
+	// Idle connections are closed until MinIdle remain
+	stats := session.Stats()
+	c.Assert(stats.OpenConnections, test.Equals, 1)
+	c.Assert(stats.Idle, test.Equals, 1)
+	c.Assert(stats.IdleTimeoutClosed, test.Equals, int64(3))
+
+	err = Expr("Hello World").Exec(session)
+	c.Assert(err, test.IsNil)
+	c.Assert(session.Stats().IdleTimeoutClosed, test.Equals, int64(3))
+}
+
+func (s *RethinkSuite) TestSessionReadMode(c *test.C) {
+	session, err := Connect(ConnectOpts{
+		Address:  url,
+		ReadMode: ReadModeOutdated,
+	})
+	c.Assert(err, test.IsNil)
+	defer session.Close()
+
+	q, err := session.newQuery(Expr(1), map[string]interface{}{})
+	c.Assert(err, test.IsNil)
+	c.Assert(q.Opts["read_mode"], test.Equals, ReadModeOutdated)
+
+	q, err = session.newQuery(Expr(1), RunOpts{ReadMode: ReadModeMajority}.toMap())
+	c.Assert(err, test.IsNil)
+	c.Assert(q.Opts["read_mode"], test.Equals, ReadModeMajority)
+
+	var response int
+	err = Expr(1).ReadOne(&response, session)
+	c.Assert(err, test.IsNil)
+	c.Assert(response, test.Equals, 1)
+}
+
+func (s *RethinkSuite) TestSessionHooks(c *test.C) {
+	var mu sync.Mutex
+	var connected, disconnected []ConnectionEvent
+
+	session, err := Connect(ConnectOpts{
+		Address: url,
+		OnConnect: func(e ConnectionEvent) {
+			mu.Lock()
+			connected = append(connected, e)
+			mu.Unlock()
+		},
+		OnDisconnect: func(e ConnectionEvent) {
+			mu.Lock()
+			disconnected = append(disconnected, e)
+			mu.Unlock()
+		},
+	})
+	c.Assert(err, test.IsNil)
+
+	err = Expr("Hello World").Exec(session)
+	c.Assert(err, test.IsNil)
+
+	err = session.Close()
+	c.Assert(err, test.IsNil)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	c.Assert(len(connected) > 0, test.Equals, true)
+	c.Assert(disconnected, test.HasLen, len(connected))
+	for _, e := range disconnected {
+		c.Assert(e.Address, test.Equals, url)
+		c.Assert(e.Err, test.IsNil)
+	}
+}
+
+func (s *RethinkSuite) TestSessionTrace(c *test.C) {
+	var mu sync.Mutex
+	var events []TraceEvent
+
+	session, err := Connect(ConnectOpts{
+		Address: url,
+		Trace: func(e TraceEvent) {
+			mu.Lock()
+			events = append(events, e)
+			mu.Unlock()
+		},
+	})
+	c.Assert(err, test.IsNil)
+	defer session.Close()
+
+	err = Expr("Hello World").Exec(session)
+	c.Assert(err, test.IsNil)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	// Connecting also sends SERVER_INFO queries so find the START query
+	var sent, received *TraceEvent
+	for i, e := range events {
+		if !e.Received && e.QueryType == p.Query_START {
+			sent = &events[i]
+		} else if e.Received && sent != nil && e.Token == sent.Token {
+			received = &events[i]
+		}
+	}
+
+	c.Assert(sent, test.NotNil)
+	c.Assert(string(sent.Data), test.Matches, `.*"Hello World".*`)
+	c.Assert(sent.Size, test.Equals, len(sent.Data)+12)
+	c.Assert(received, test.NotNil)
+	c.Assert(received.ResponseType, test.Equals, p.Response_SUCCESS_ATOM)
+}
+
+func (s *RethinkSuite) TestSessionReconnectOptsBackOff(c *test.C) {
+	b := (&ReconnectOpts{}).backOff().(*backoff.ExponentialBackOff)
+	c.Assert(b.InitialInterval, test.Equals, backoff.DefaultInitialInterval)
+	c.Assert(b.MaxInterval, test.Equals, backoff.DefaultMaxInterval)
+	c.Assert(b.MaxElapsedTime, test.Equals, time.Duration(0))
+
+	b = (&ReconnectOpts{
+		InitialInterval: time.Second,
+		MaxInterval:     time.Minute,
+		Multiplier:      2,
+		MaxElapsedTime:  time.Hour,
+	}).backOff().(*backoff.ExponentialBackOff)
+	c.Assert(b.InitialInterval, test.Equals, time.Second)
+	c.Assert(b.MaxInterval, test.Equals, time.Minute)
+	c.Assert(b.Multiplier, test.Equals, float64(2))
+	c.Assert(b.MaxElapsedTime, test.Equals, time.Hour)
+}
+
+func (s *RethinkSuite) TestSessionMaxQueriesPerConn(c *test.C) {
+	session, err := Connect(ConnectOpts{
+		Address:           url,
+		MaxOpen:           1,
+		MaxQueriesPerConn: 10,
+	})
+	c.Assert(err, test.IsNil)
+	defer session.Close()
+
+	// Open several cursors which share the only connection
+	var cursors []*Cursor
+	for i := 0; i < 3; i++ {
+		res, err := Range(1000).Run(session, RunOpts{MaxBatchRows: 10})
+		c.Assert(err, test.IsNil)
+		cursors = append(cursors, res)
+	}
+
+	var response int
+	err = Expr(1).ReadOne(&response, session)
+	c.Assert(err, test.IsNil)
+	c.Assert(response, test.Equals, 1)
+
+	for _, res := range cursors {
+		var rows []int
+		c.Assert(res.All(&rows), test.IsNil)
+		c.Assert(rows, test.HasLen, 1000)
+	}
+
+	stats := session.Stats()
+	c.Assert(stats.OpenConnections, test.Equals, 1)
+	c.Assert(stats.WaitCount, test.Equals, int64(0))
+}
+
+func (s *RethinkSuite) TestSessionCloseDrain(c *test.C) {
+	session, err := Connect(ConnectOpts{
+		Address: url,
+	})
+	c.Assert(err, test.IsNil)
+
+	DB("test").TableCreate("changes").Exec(session)
+	DB("test").Table("changes").Wait().Exec(session)
+
+	feed, err := DB("test").Table("changes").Changes().Run(session)
+	c.Assert(err, test.IsNil)
+
+	res, err := Range(1000).Run(session, RunOpts{MaxBatchRows: 10})
+	c.Assert(err, test.IsNil)
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		res.Close()
+	}()
+
+	// Close stops the feed and waits for the cursor to be closed
+	err = session.Close(CloseOpts{Drain: true, Timeout: 5 * time.Second})
+	c.Assert(err, test.IsNil)
+	c.Assert(session.IsConnected(), test.Equals, false)
+
+	var change interface{}
+	c.Assert(feed.Next(&change), test.Equals, false)
+	c.Assert(feed.Err(), test.IsNil)
+}
@@ -0,0 +1,174 @@
+package types
+
+import (
+	"time"
+
+	"gopkg.in/gorethink/gorethink.v3/encoding"
+)
+
+// NullString represents a string which may be null or missing.
+//
+// The Null types can be used as struct fields to tell whether a field was
+// missing from a decoded document, was null or contained a value. When
+// encoded the value is used if Valid is true, otherwise null is used. Values
+// which are neither Valid nor Present are empty, so fields tagged with
+// omitempty are not encoded when they were missing from the decoded document,
+// to encode null set Present to true.
+type NullString struct {
+	String string
+	// Valid is true if the value is not null
+	Valid bool
+	// Present is true if the field was present when decoding, even if null
+	Present bool
+}
+
+func (n NullString) MarshalRQL() (interface{}, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return encoding.Encode(n.String)
+}
+
+func (n NullString) IsEmptyRQL() bool {
+	return !n.Valid && !n.Present
+}
+
+func (n *NullString) UnmarshalRQL(data interface{}) error {
+	*n = NullString{Present: true}
+	if data == nil {
+		return nil
+	}
+	if err := encoding.Decode(&n.String, data); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
+
+// NullInt64 represents an int64 which may be null or missing.
+type NullInt64 struct {
+	Int64 int64
+	// Valid is true if the value is not null
+	Valid bool
+	// Present is true if the field was present when decoding, even if null
+	Present bool
+}
+
+func (n NullInt64) MarshalRQL() (interface{}, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return encoding.Encode(n.Int64)
+}
+
+func (n NullInt64) IsEmptyRQL() bool {
+	return !n.Valid && !n.Present
+}
+
+func (n *NullInt64) UnmarshalRQL(data interface{}) error {
+	*n = NullInt64{Present: true}
+	if data == nil {
+		return nil
+	}
+	if err := encoding.Decode(&n.Int64, data); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
+
+// NullFloat64 represents a float64 which may be null or missing.
+type NullFloat64 struct {
+	Float64 float64
+	// Valid is true if the value is not null
+	Valid bool
+	// Present is true if the field was present when decoding, even if null
+	Present bool
+}
+
+func (n NullFloat64) MarshalRQL() (interface{}, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return encoding.Encode(n.Float64)
+}
+
+func (n NullFloat64) IsEmptyRQL() bool {
+	return !n.Valid && !n.Present
+}
+
+func (n *NullFloat64) UnmarshalRQL(data interface{}) error {
+	*n = NullFloat64{Present: true}
+	if data == nil {
+		return nil
+	}
+	if err := encoding.Decode(&n.Float64, data); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
+
+// NullBool represents a bool which may be null or missing.
+type NullBool struct {
+	Bool bool
+	// Valid is true if the value is not null
+	Valid bool
+	// Present is true if the field was present when decoding, even if null
+	Present bool
+}
+
+func (n NullBool) MarshalRQL() (interface{}, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return encoding.Encode(n.Bool)
+}
+
+func (n NullBool) IsEmptyRQL() bool {
+	return !n.Valid && !n.Present
+}
+
+func (n *NullBool) UnmarshalRQL(data interface{}) error {
+	*n = NullBool{Present: true}
+	if data == nil {
+		return nil
+	}
+	if err := encoding.Decode(&n.Bool, data); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
+
+// NullTime represents a time.Time which may be null or missing.
+type NullTime struct {
+	Time time.Time
+	// Valid is true if the value is not null
+	Valid bool
+	// Present is true if the field was present when decoding, even if null
+	Present bool
+}
+
+func (n NullTime) MarshalRQL() (interface{}, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return encoding.Encode(n.Time)
+}
+
+func (n NullTime) IsEmptyRQL() bool {
+	return !n.Valid && !n.Present
+}
+
+func (n *NullTime) UnmarshalRQL(data interface{}) error {
+	*n = NullTime{Present: true}
+	if data == nil {
+		return nil
+	}
+	if err := encoding.Decode(&n.Time, data); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
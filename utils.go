@@ -1,7 +1,10 @@
 package gorethink
 
 import (
+	"encoding/json"
+	"fmt"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"sync/atomic"
@@ -48,12 +51,15 @@ func newQuery(t Term, qopts map[string]interface{}, copts *ConnectOpts) (q Query
 			return
 		}
 	}
-	if copts.Database != "" {
+	if _, ok := queryOpts["db"]; !ok && copts.Database != "" {
 		queryOpts["db"], err = DB(copts.Database).Build()
 		if err != nil {
 			return
 		}
 	}
+	if _, ok := queryOpts["read_mode"]; !ok && copts.ReadMode != "" {
+		queryOpts["read_mode"] = copts.ReadMode
+	}
 
 	builtTerm, err := t.Build()
 	if err != nil {
@@ -93,6 +99,14 @@ func makeFunc(f interface{}) Term {
 	value := reflect.ValueOf(f)
 	valueType := value.Type()
 
+	if err := checkFuncSignature(valueType); err != nil {
+		return Term{
+			name:     "func",
+			termType: p.Term_FUNC,
+			lastErr:  RQLDriverError{rqlError("Function " + err.Error())},
+		}
+	}
+
 	var argNums = make([]interface{}, valueType.NumIn())
 	var args = make([]reflect.Value, valueType.NumIn())
 	for i := 0; i < valueType.NumIn(); i++ {
@@ -100,22 +114,258 @@ func makeFunc(f interface{}) Term {
 		varID := atomic.AddInt64(&nextVarID, 1)
 		args[i] = reflect.ValueOf(constructRootTerm("var", p.Term_VAR, []interface{}{varID}, map[string]interface{}{}))
 		argNums[i] = varID
+	}
+
+	body := value.Call(args)[0].Interface()
+	argsArr := makeArray(convertTermList(argNums))
 
-		// make sure all input arguments are of type Term
+	return constructRootTerm("func", p.Term_FUNC, []interface{}{argsArr, body}, map[string]interface{}{})
+}
+
+// checkFuncSignature returns an error if a function of the given type cannot
+// be converted to a ReQL function, all arguments must be of type Term or
+// interface {} and it must return a single value.
+func checkFuncSignature(valueType reflect.Type) error {
+	if valueType.IsVariadic() {
+		return fmt.Errorf("must not be variadic")
+	}
+	for i := 0; i < valueType.NumIn(); i++ {
 		argValueTypeName := valueType.In(i).String()
 		if argValueTypeName != "gorethink.Term" && argValueTypeName != "interface {}" {
-			panic("Function argument is not of type Term or interface {}")
+			return fmt.Errorf("argument %d is of type %s, expected Term or interface {}", i+1, argValueTypeName)
 		}
 	}
-
 	if valueType.NumOut() != 1 {
-		panic("Function does not have a single return value")
+		return fmt.Errorf("must have a single return value, has %d", valueType.NumOut())
 	}
 
-	body := value.Call(args)[0].Interface()
-	argsArr := makeArray(convertTermList(argNums))
+	return nil
+}
 
-	return constructRootTerm("func", p.Term_FUNC, []interface{}{argsArr, body}, map[string]interface{}{})
+// checkFuncArg returns an error naming the term if f is a Go function which
+// cannot be used as a ReQL function called with arity arguments. Functions
+// without any arguments can be called with any number of arguments and values
+// which are not functions are ignored.
+func checkFuncArg(name string, f interface{}, arity int) error {
+	value := reflect.ValueOf(f)
+	if value.Kind() != reflect.Func {
+		return nil
+	}
+
+	if err := checkFuncSignature(value.Type()); err != nil {
+		return RQLDriverError{rqlError(fmt.Sprintf("%s function %s", name, err))}
+	}
+	if n := value.Type().NumIn(); n != 0 && n != arity {
+		arguments := "arguments"
+		if n == 1 {
+			arguments = "argument"
+		}
+		return RQLDriverError{rqlError(fmt.Sprintf("%s function takes %d %s but was given %d", name, n, arguments, arity))}
+	}
+
+	return nil
+}
+
+// termNames contains the names of the terms created by UnmarshalQuery when the
+// name used by this driver does not match the name of the term type.
+var termNames = map[p.Term_TermType]string{
+	p.Term_BRACKET:        "AtIndex",
+	p.Term_DB:             "DB",
+	p.Term_DB_CREATE:      "DBCreate",
+	p.Term_DB_DROP:        "DBDrop",
+	p.Term_DB_LIST:        "DBList",
+	p.Term_FOR_EACH:       "Foreach",
+	p.Term_FUNCALL:        "Do",
+	p.Term_GEOJSON:        "GeoJSON",
+	p.Term_GET_FIELD:      "Field",
+	p.Term_IMPLICIT_VAR:   "Doc",
+	p.Term_ISO8601:        "ISO8601",
+	p.Term_JAVASCRIPT:     "Js",
+	p.Term_MAXVAL:         "MaxVal",
+	p.Term_MINVAL:         "MinVal",
+	p.Term_TO_GEOJSON:     "ToGeoJSON",
+	p.Term_TO_ISO8601:     "ToISO8601",
+	p.Term_TO_JSON_STRING: "ToJSON",
+	p.Term_UUID:           "UUID",
+	p.Term_FUNC:           "func",
+	p.Term_VAR:            "var",
+}
+
+func termName(termType p.Term_TermType) string {
+	if name, ok := termNames[termType]; ok {
+		return name
+	}
+
+	name := ""
+	for _, s := range strings.Split(strings.ToLower(termType.String()), "_") {
+		name += strings.Title(s)
+	}
+
+	return name
+}
+
+// parseTerm converts a decoded JSON term into a Term, vars maps the variable
+// IDs used in the JSON to the IDs allocated by this driver.
+func parseTerm(v interface{}, vars map[int64]int64) (Term, error) {
+	switch v := v.(type) {
+	case []interface{}:
+		return parseTermArray(v, vars)
+	case map[string]interface{}:
+		obj := make(termsObj, len(v))
+		for k, x := range v {
+			t, err := parseTerm(x, vars)
+			if err != nil {
+				return Term{}, err
+			}
+			obj[k] = t
+		}
+
+		return makeObject(obj), nil
+	case json.Number:
+		return Expr(parseNumber(v)), nil
+	default:
+		return Expr(v), nil
+	}
+}
+
+func parseTermArray(v []interface{}, vars map[int64]int64) (Term, error) {
+	if len(v) == 0 || len(v) > 3 {
+		return Term{}, parseTermError("Expected [type, args, optargs] but found %v", v)
+	}
+
+	i, ok := parseInt(v[0])
+	if _, known := p.Term_TermType_name[int32(i)]; !ok || !known || i == int64(p.Term_DATUM) {
+		return Term{}, parseTermError("Invalid term type %v", v[0])
+	}
+	termType := p.Term_TermType(i)
+
+	var jsonArgs []interface{}
+	var jsonOptArgs map[string]interface{}
+	if len(v) > 1 {
+		if jsonArgs, ok = v[1].([]interface{}); !ok {
+			return Term{}, parseTermError("Expected %s arguments to be an array but found %v", termType, v[1])
+		}
+	}
+	if len(v) > 2 {
+		if jsonOptArgs, ok = v[2].(map[string]interface{}); !ok {
+			return Term{}, parseTermError("Expected %s optional arguments to be an object but found %v", termType, v[2])
+		}
+	}
+
+	switch termType {
+	case p.Term_FUNC:
+		return parseFunc(jsonArgs, vars)
+	case p.Term_VAR:
+		id, ok := int64(0), len(jsonArgs) == 1
+		if ok {
+			id, ok = parseInt(jsonArgs[0])
+		}
+		if !ok {
+			return Term{}, parseTermError("Expected VAR to have a single numeric ID but found %v", jsonArgs)
+		}
+		// Variables which are not bound by a parsed function keep their ID
+		if newID, bound := vars[id]; bound {
+			id = newID
+		}
+
+		return constructRootTerm("var", p.Term_VAR, []interface{}{id}, map[string]interface{}{}), nil
+	}
+
+	args := make(termsList, len(jsonArgs))
+	for i, x := range jsonArgs {
+		t, err := parseTerm(x, vars)
+		if err != nil {
+			return Term{}, err
+		}
+		args[i] = t
+	}
+
+	var optArgs termsObj
+	if len(jsonOptArgs) > 0 {
+		optArgs = make(termsObj, len(jsonOptArgs))
+		for k, x := range jsonOptArgs {
+			t, err := parseTerm(x, vars)
+			if err != nil {
+				return Term{}, err
+			}
+			optArgs[k] = t
+		}
+	}
+
+	if termType == p.Term_MAKE_ARRAY {
+		return makeArray(args), nil
+	}
+
+	return Term{
+		name:     termName(termType),
+		rootTerm: len(args) == 0 || args[0].termType == p.Term_DATUM || termType == p.Term_FUNCALL,
+		termType: termType,
+		args:     args,
+		optArgs:  optArgs,
+	}, nil
+}
+
+// parseFunc converts the arguments of a FUNC term, allocating new IDs for the
+// function parameters.
+func parseFunc(jsonArgs []interface{}, vars map[int64]int64) (Term, error) {
+	var ids []interface{}
+	if len(jsonArgs) == 2 {
+		if params, ok := jsonArgs[0].([]interface{}); ok && len(params) == 2 {
+			if typ, ok := parseInt(params[0]); ok && typ == int64(p.Term_MAKE_ARRAY) {
+				ids, _ = params[1].([]interface{})
+			}
+		}
+	}
+	if ids == nil {
+		return Term{}, parseTermError("Expected FUNC to have a parameter list and body but found %v", jsonArgs)
+	}
+
+	scope := make(map[int64]int64, len(vars)+len(ids))
+	for k, id := range vars {
+		scope[k] = id
+	}
+
+	params := make([]interface{}, len(ids))
+	for i, x := range ids {
+		id, ok := parseInt(x)
+		if !ok {
+			return Term{}, parseTermError("Invalid FUNC parameter %v", x)
+		}
+		newID := atomic.AddInt64(&nextVarID, 1)
+		scope[id] = newID
+		params[i] = newID
+	}
+
+	body, err := parseTerm(jsonArgs[1], scope)
+	if err != nil {
+		return Term{}, err
+	}
+
+	return constructRootTerm("func", p.Term_FUNC, []interface{}{makeArray(convertTermList(params)), body}, map[string]interface{}{}), nil
+}
+
+// parseInt returns the value of a decoded JSON number if it is an integer.
+func parseInt(v interface{}) (int64, bool) {
+	n, ok := v.(json.Number)
+	if !ok {
+		return 0, false
+	}
+	i, err := n.Int64()
+
+	return i, err == nil
+}
+
+func parseNumber(n json.Number) interface{} {
+	if i, err := n.Int64(); err == nil {
+		return i
+	}
+	f, _ := n.Float64()
+
+	return f
+}
+
+func parseTermError(format string, args ...interface{}) error {
+	return RQLDriverError{rqlError("Error parsing query: " + fmt.Sprintf(format, args...))}
 }
 
 func funcWrap(value interface{}) Term {
@@ -130,11 +380,12 @@ func funcWrap(value interface{}) Term {
 }
 
 func funcWrapArgs(args []interface{}) []interface{} {
+	wrapped := make([]interface{}, len(args))
 	for i, arg := range args {
-		args[i] = funcWrap(arg)
+		wrapped[i] = funcWrap(arg)
 	}
 
-	return args
+	return wrapped
 }
 
 // implVarScan recursivly checks a value to see if it contains an
@@ -171,6 +422,22 @@ func optArgsToMap(optArgs OptArgs) map[string]interface{} {
 	return map[string]interface{}{}
 }
 
+// withDBOptArg adds the db optional argument to opts, database names are
+// converted to a DB term as the server expects.
+func withDBOptArg(opts map[string]interface{}, db, deprecated interface{}) map[string]interface{} {
+	if db == nil {
+		db = deprecated
+	}
+	if name, ok := db.(string); ok {
+		db = DB(name)
+	}
+	if db != nil {
+		opts["db"] = db
+	}
+
+	return opts
+}
+
 // Convert a list into a slice of terms
 func convertTermList(l []interface{}) termsList {
 	if len(l) == 0 {
@@ -202,19 +469,7 @@ func convertTermObj(o map[string]interface{}) termsObj {
 // Helper functions for debugging
 
 func allArgsToStringSlice(args termsList, optArgs termsObj) []string {
-	allArgs := make([]string, len(args)+len(optArgs))
-	i := 0
-
-	for _, v := range args {
-		allArgs[i] = v.String()
-		i++
-	}
-	for k, v := range optArgs {
-		allArgs[i] = k + "=" + v.String()
-		i++
-	}
-
-	return allArgs
+	return append(argsToStringSlice(args), optArgsToStringSlice(optArgs)...)
 }
 
 func argsToStringSlice(args termsList) []string {
@@ -227,18 +482,29 @@ func argsToStringSlice(args termsList) []string {
 	return allArgs
 }
 
+// optArgsToStringSlice returns the optional arguments sorted by name so that
+// the string representation of a term is stable.
 func optArgsToStringSlice(optArgs termsObj) []string {
-	allArgs := make([]string, len(optArgs))
-	i := 0
+	keys := sortedTermsObjKeys(optArgs)
 
-	for k, v := range optArgs {
-		allArgs[i] = k + "=" + v.String()
-		i++
+	allArgs := make([]string, len(keys))
+	for i, k := range keys {
+		allArgs[i] = k + "=" + optArgs[k].String()
 	}
 
 	return allArgs
 }
 
+func sortedTermsObjKeys(obj termsObj) []string {
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
 func splitAddress(address string) (hostname string, port int) {
 	hostname = "localhost"
 	port = 28015
@@ -270,7 +536,39 @@ func encode(data interface{}) (interface{}, error) {
 
 // shouldRetryQuery checks the result of a query and returns true if the query
 // should be retried
-func shouldRetryQuery(q Query, err error) bool {
+func shouldRetryQuery(q Query, err error, policy *RetryPolicy) bool {
+	if err == nil {
+		return false
+	}
+	if policy == nil {
+		return isConnectionError(err)
+	}
+	if policy.Retryable != nil {
+		return policy.Retryable(err)
+	}
+
+	return IsTransientError(err)
+}
+
+// validateBound returns an error if a left_bound or right_bound optional
+// argument is a string other than BoundOpen or BoundClosed. Other values, such
+// as terms, are checked by the server.
+func validateBound(name string, bound interface{}) error {
+	if s, ok := bound.(string); ok && s != BoundOpen && s != BoundClosed {
+		return RQLDriverError{rqlError(fmt.Sprintf("Invalid %s %q, must be %q or %q", name, s, BoundOpen, BoundClosed))}
+	}
+
+	return nil
+}
+
+// panicError converts a value recovered from a panic to an error.
+func panicError(r interface{}) error {
+	return RQLDriverError{rqlError(fmt.Sprintf("Recovered from panic: %v", r))}
+}
+
+// isConnectionError returns true if the error was caused by the connection to
+// the server failing.
+func isConnectionError(err error) bool {
 	if err == nil {
 		return false
 	}